@@ -10,12 +10,18 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/ochapman/godmi"
+	"os"
 	"reflect"
 )
 
+var jsonOutput = flag.Bool("json", false, "print the structures as JSON")
+
 func main() {
+	flag.Parse()
 	godmi.Init()
 	infos := []interface{}{
 		godmi.GetPortInformation(),
@@ -45,7 +51,11 @@ func main() {
 		godmi.Get_64BitMemoryErrorInformation(),
 		godmi.GetManagementDevice(),
 		godmi.GetManagementDeviceComponent(),
-		godmi.GetManagementDeviceThresholdData(),
+	}
+	for _, m := range godmi.GetManagementDeviceThresholdData() {
+		infos = append(infos, m)
+	}
+	infos = append(infos,
 		godmi.GetMemoryChannel(),
 		godmi.GetIPMIDeviceInformation(),
 		godmi.GetSystemPowerSupply(),
@@ -55,12 +65,25 @@ func main() {
 		godmi.GetBIOSInformation(),
 		godmi.GetSystemInformation(),
 		godmi.GetBaseboardInformation(),
-	}
+	)
+	var as []godmi.AnnotatedStructure
 	for _, info := range infos {
 		rv := reflect.ValueOf(info)
 		if rv.IsNil() {
 			continue
 		}
-		fmt.Println(info)
+		as = append(as, godmi.Annotate(info))
+	}
+	if *jsonOutput {
+		b, err := json.MarshalIndent(as, "", "\t")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(string(b))
+		return
+	}
+	for _, a := range as {
+		fmt.Println(a)
 	}
 }
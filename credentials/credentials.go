@@ -0,0 +1,166 @@
+// Package credentials extracts what a hypervisor passes to a guest through
+// SMBIOS: systemd credentials and kernel command line additions in the OEM
+// Strings (type 11), and the cloud-init datasource in the system serial
+// number (type 1).
+package credentials
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ochapman/godmi"
+)
+
+const (
+	credentialPrefix         = "io.systemd.credential:"
+	binaryCredentialPrefix   = "io.systemd.credential.binary:"
+	kernelCmdlineExtraPrefix = "io.systemd.stub.kernel-cmdline-extra="
+)
+
+// Credential is a systemd credential
+type Credential struct {
+	Name  string
+	Value []byte
+}
+
+func (c Credential) String() string {
+	return fmt.Sprintf("%s (%d bytes)", c.Name, len(c.Value))
+}
+
+// Datasource is a cloud-init datasource selection such as
+// ds=nocloud;s=http://10.0.0.1/
+type Datasource struct {
+	Name string
+	// Params holds the remaining key=value pairs, with the abbreviations
+	// s, h and i expanded to seedfrom, local-hostname and instance-id
+	Params map[string]string
+}
+
+func (d Datasource) String() string {
+	s := "ds=" + d.Name
+	for _, k := range []string{"seedfrom", "local-hostname", "instance-id"} {
+		if v, ok := d.Params[k]; ok {
+			s += ";" + k + "=" + v
+		}
+	}
+	return s
+}
+
+// Credentials is everything found in the SMBIOS table
+type Credentials struct {
+	Credentials        []Credential
+	KernelCmdlineExtra []string
+	Datasource         *Datasource
+}
+
+// Lookup returns the credential called name
+func (c Credentials) Lookup(name string) (Credential, bool) {
+	for _, cr := range c.Credentials {
+		if cr.Name == name {
+			return cr, true
+		}
+	}
+	return Credential{}, false
+}
+
+// validName reports whether name is usable as a credential name, which
+// systemd requires to be a valid file name
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." &&
+		len(name) <= 255 && !strings.ContainsAny(name, "/\x00")
+}
+
+func parseCredential(s string, binary bool) (Credential, error) {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return Credential{}, fmt.Errorf("credentials: %q has no value", s)
+	}
+	c := Credential{Name: s[:i]}
+	if !validName(c.Name) {
+		return Credential{}, fmt.Errorf("credentials: invalid name %q", c.Name)
+	}
+	if !binary {
+		c.Value = []byte(s[i+1:])
+		return c, nil
+	}
+	v, err := base64.StdEncoding.DecodeString(s[i+1:])
+	if err != nil {
+		return Credential{}, fmt.Errorf("credentials: %s: %v", c.Name, err)
+	}
+	c.Value = v
+	return c, nil
+}
+
+// ParseDatasource parses a cloud-init datasource selection. It reports
+// false if s does not start with ds=.
+func ParseDatasource(s string) (*Datasource, bool) {
+	if !strings.HasPrefix(s, "ds=") {
+		return nil, false
+	}
+	fields := strings.Split(s[len("ds="):], ";")
+	d := &Datasource{Name: fields[0], Params: make(map[string]string)}
+	if d.Name == "" {
+		return nil, false
+	}
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			continue
+		}
+		switch kv[0] {
+		case "s":
+			kv[0] = "seedfrom"
+		case "h":
+			kv[0] = "local-hostname"
+		case "i":
+			kv[0] = "instance-id"
+		}
+		d.Params[kv[0]] = kv[1]
+	}
+	return d, true
+}
+
+// Parse extracts credentials and kernel command line additions from OEM
+// strings and the datasource from a system serial number. Malformed
+// credentials are skipped and the first such error is returned along with
+// everything else that was found.
+func Parse(oemStrings []string, serialNumber string) (Credentials, error) {
+	var c Credentials
+	var err error
+	for _, s := range oemStrings {
+		var cr Credential
+		var e error
+		switch {
+		case strings.HasPrefix(s, credentialPrefix):
+			cr, e = parseCredential(s[len(credentialPrefix):], false)
+		case strings.HasPrefix(s, binaryCredentialPrefix):
+			cr, e = parseCredential(s[len(binaryCredentialPrefix):], true)
+		case strings.HasPrefix(s, kernelCmdlineExtraPrefix):
+			c.KernelCmdlineExtra = append(c.KernelCmdlineExtra, s[len(kernelCmdlineExtraPrefix):])
+			continue
+		default:
+			continue
+		}
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+			continue
+		}
+		c.Credentials = append(c.Credentials, cr)
+	}
+	if d, ok := ParseDatasource(serialNumber); ok {
+		c.Datasource = d
+	}
+	return c, err
+}
+
+// Get parses the OEM strings and system serial number of the SMBIOS table
+func Get() (Credentials, error) {
+	var serial string
+	if si := godmi.GetSystemInformation(); si != nil {
+		serial = si.SerialNumber
+	}
+	return Parse(godmi.GetOEMStringValues(), serial)
+}
@@ -0,0 +1,45 @@
+package credentials
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	oem := []string{
+		"Dell System",
+		"io.systemd.credential:passwd.hashed-password.root=$6$abc",
+		"io.systemd.credential.binary:ssh.authorized_keys.root=c3NoLWVkMjU1MTkgQUFBQQ==",
+		"io.systemd.credential.binary:broken=!!!",
+		"io.systemd.credential:../etc=x",
+		"io.systemd.stub.kernel-cmdline-extra=console=ttyS0 quiet",
+	}
+	c, err := Parse(oem, "ds=nocloud;s=http://10.0.0.1/seed/;h=node1;i=iid-01")
+	if err == nil {
+		t.Error("Parse: expected error for malformed credential")
+	}
+	if len(c.Credentials) != 2 {
+		t.Fatalf("Parse: got credentials %v", c.Credentials)
+	}
+	if cr, ok := c.Lookup("passwd.hashed-password.root"); !ok || string(cr.Value) != "$6$abc" {
+		t.Errorf("Lookup: got %v %v", cr, ok)
+	}
+	if cr, ok := c.Lookup("ssh.authorized_keys.root"); !ok || string(cr.Value) != "ssh-ed25519 AAAA" {
+		t.Errorf("Lookup binary: got %q %v", cr.Value, ok)
+	}
+	if len(c.KernelCmdlineExtra) != 1 || c.KernelCmdlineExtra[0] != "console=ttyS0 quiet" {
+		t.Errorf("KernelCmdlineExtra: got %q", c.KernelCmdlineExtra)
+	}
+	d := c.Datasource
+	if d == nil || d.Name != "nocloud" || d.Params["seedfrom"] != "http://10.0.0.1/seed/" ||
+		d.Params["local-hostname"] != "node1" || d.Params["instance-id"] != "iid-01" {
+		t.Errorf("Datasource: got %v", d)
+	}
+}
+
+func TestParseDatasource(t *testing.T) {
+	for _, s := range []string{"", "VMware-42 1a", "ds=", "ds"} {
+		if d, ok := ParseDatasource(s); ok {
+			t.Errorf("ParseDatasource(%q): got %v", s, d)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package edac reads the per DIMM error counters of the Linux EDAC
+// subsystem and attaches them to the SMBIOS memory devices (type 17) they
+// belong to.
+package edac
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs"
+)
+
+// DefaultRoot is where the kernel lists memory controllers
+const DefaultRoot = "/sys/devices/system/edac/mc"
+
+// DIMM is a memory module as seen by an EDAC memory controller
+type DIMM struct {
+	Controller int
+	Index      int
+	Label      string
+	// Location is the position of the DIMM in the controller, such as
+	// "channel 0 slot 1", with its numbers in LocationIndex
+	Location      string
+	LocationIndex []int
+	SizeMB        uint64
+	MemType       string
+	EdacMode      string
+	CECount       uint64
+	UECount       uint64
+}
+
+func (d DIMM) String() string {
+	return fmt.Sprintf("mc%d/dimm%d %q %s %dMB CE=%d UE=%d",
+		d.Controller, d.Index, d.Label, d.Location, d.SizeMB, d.CECount, d.UECount)
+}
+
+// parseLocation returns the numbers of an EDAC location such as
+// "csrow 0 channel 1" or "channel 0 slot 1"
+func parseLocation(s string) []int {
+	var is []int
+	for _, f := range strings.Fields(s) {
+		if i, err := strconv.Atoi(f); err == nil {
+			is = append(is, i)
+		}
+	}
+	return is
+}
+
+// index returns the number following prefix in name, such as 3 for mc3
+func index(name, prefix string) (int, bool) {
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false
+	}
+	i, err := strconv.Atoi(name[len(prefix):])
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+func entries(dir, prefix string) []int {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var is []int
+	for _, fi := range fis {
+		if i, ok := index(fi.Name(), prefix); ok {
+			is = append(is, i)
+		}
+	}
+	sort.Ints(is)
+	return is
+}
+
+// Scan reads every DIMM of every memory controller under root, which is
+// normally DefaultRoot. DIMMs are returned in controller and DIMM order.
+func Scan(root string) ([]DIMM, error) {
+	if _, err := ioutil.ReadDir(root); err != nil {
+		return nil, err
+	}
+	var ds []DIMM
+	for _, mc := range entries(root, "mc") {
+		mcdir := filepath.Join(root, fmt.Sprintf("mc%d", mc))
+		for _, i := range entries(mcdir, "dimm") {
+			dir := filepath.Join(mcdir, fmt.Sprintf("dimm%d", i))
+			d := DIMM{
+				Controller: mc,
+				Index:      i,
+				Label:      sysfs.ReadString(dir, "dimm_label"),
+				Location:   sysfs.ReadString(dir, "dimm_location"),
+				SizeMB:     sysfs.ReadUint(dir, "size"),
+				MemType:    sysfs.ReadString(dir, "dimm_mem_type"),
+				EdacMode:   sysfs.ReadString(dir, "dimm_edac_mode"),
+				CECount:    sysfs.ReadUint(dir, "dimm_ce_count"),
+				UECount:    sysfs.ReadUint(dir, "dimm_ue_count"),
+			}
+			d.LocationIndex = parseLocation(d.Location)
+			ds = append(ds, d)
+		}
+	}
+	return ds, nil
+}
+
+// MatchMethod tells how an EDAC DIMM was tied to a memory device
+type MatchMethod byte
+
+const (
+	MatchNone MatchMethod = iota
+	MatchLabel
+	MatchPosition
+)
+
+func (m MatchMethod) String() string {
+	methods := [...]string{
+		"None",
+		"Label",
+		"Position",
+	}
+	if int(m) < len(methods) {
+		return methods[m]
+	}
+	return godmi.OUT_OF_SPEC
+}
+
+// DIMMErrors are the error counts of a physical DIMM. Device is nil for an
+// EDAC DIMM that could not be tied to a memory device, and DIMM is nil for
+// an installed memory device that EDAC does not report.
+type DIMMErrors struct {
+	Device *godmi.MemoryDevice
+	DIMM   *DIMM
+	Method MatchMethod
+}
+
+// CECount returns the corrected error count
+func (e DIMMErrors) CECount() uint64 {
+	if e.DIMM == nil {
+		return 0
+	}
+	return e.DIMM.CECount
+}
+
+// UECount returns the uncorrected error count
+func (e DIMMErrors) UECount() uint64 {
+	if e.DIMM == nil {
+		return 0
+	}
+	return e.DIMM.UECount
+}
+
+func (e DIMMErrors) String() string {
+	name := "unknown"
+	if e.Device != nil {
+		name = strings.TrimSpace(e.Device.BankLocator + " " + e.Device.DeviceLocator)
+	}
+	if e.DIMM == nil {
+		return fmt.Sprintf("%s: not reported by EDAC", name)
+	}
+	return fmt.Sprintf("%s: CE=%d UE=%d (mc%d/dimm%d, by %s)",
+		name, e.DIMM.CECount, e.DIMM.UECount, e.DIMM.Controller, e.DIMM.Index, e.Method)
+}
+
+func normalize(s string) string {
+	var b []byte
+	for _, c := range []byte(strings.ToLower(s)) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// labelMatches reports whether an EDAC label names the memory device. The
+// label may be the device locator alone, or prefixed by the bank locator or
+// by a socket or controller name, as in "CPU0_DIMM_A1".
+func labelMatches(label string, m *godmi.MemoryDevice) bool {
+	l := normalize(label)
+	dev := normalize(m.DeviceLocator)
+	if l == "" || dev == "" {
+		return false
+	}
+	if l == dev || l == normalize(m.BankLocator)+dev {
+		return true
+	}
+	if !strings.HasSuffix(l, dev) {
+		return false
+	}
+	// Avoid DIMM_A1 matching DIMM_A11
+	prev := l[len(l)-len(dev)-1]
+	last := dev[0]
+	isDigit := func(c byte) bool { return c >= '0' && c <= '9' }
+	return isDigit(prev) != isDigit(last)
+}
+
+func sizeMatches(d *DIMM, m *godmi.MemoryDevice) bool {
+	b := m.SizeBytes()
+	if d.SizeMB == 0 || b == 0 {
+		return true
+	}
+	return d.SizeMB == b>>20
+}
+
+func lessDIMM(a, b *DIMM) bool {
+	if a.Controller != b.Controller {
+		return a.Controller < b.Controller
+	}
+	for i := 0; i < len(a.LocationIndex) && i < len(b.LocationIndex); i++ {
+		if a.LocationIndex[i] != b.LocationIndex[i] {
+			return a.LocationIndex[i] < b.LocationIndex[i]
+		}
+	}
+	return a.Index < b.Index
+}
+
+// Correlate ties the EDAC DIMMs to the installed memory devices.
+//
+// A DIMM is first matched by its label against the device and bank
+// locators, as long as the label names exactly one device of the same
+// size. Many firmwares leave the labels blank or fill in names that differ
+// from the silk screen, so the remaining DIMMs are then matched by
+// position: when as many DIMMs as devices are left, both are taken in
+// order, controllers and slots for EDAC and table order for SMBIOS, and
+// the pairing is kept only if every pair has the same size.
+func Correlate(mds []*godmi.MemoryDevice, dimms []DIMM) []DIMMErrors {
+	var devs []*godmi.MemoryDevice
+	for _, m := range mds {
+		if m.Installed() {
+			devs = append(devs, m)
+		}
+	}
+	var ds []*DIMM
+	for i := range dimms {
+		if dimms[i].SizeMB == 0 && dimms[i].Label == "" {
+			continue
+		}
+		ds = append(ds, &dimms[i])
+	}
+
+	matched := make(map[*godmi.MemoryDevice]DIMMErrors)
+	used := make(map[*DIMM]bool)
+	for _, d := range ds {
+		var cand *godmi.MemoryDevice
+		n := 0
+		for _, m := range devs {
+			if _, ok := matched[m]; ok {
+				continue
+			}
+			if labelMatches(d.Label, m) && sizeMatches(d, m) {
+				cand = m
+				n++
+			}
+		}
+		if n == 1 {
+			matched[cand] = DIMMErrors{Device: cand, DIMM: d, Method: MatchLabel}
+			used[d] = true
+		}
+	}
+
+	var restDevs []*godmi.MemoryDevice
+	for _, m := range devs {
+		if _, ok := matched[m]; !ok {
+			restDevs = append(restDevs, m)
+		}
+	}
+	var restDIMMs []*DIMM
+	for _, d := range ds {
+		if !used[d] {
+			restDIMMs = append(restDIMMs, d)
+		}
+	}
+	sort.SliceStable(restDIMMs, func(i, j int) bool {
+		return lessDIMM(restDIMMs[i], restDIMMs[j])
+	})
+	if len(restDevs) == len(restDIMMs) {
+		ok := true
+		for i := range restDevs {
+			if !sizeMatches(restDIMMs[i], restDevs[i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			for i, m := range restDevs {
+				matched[m] = DIMMErrors{Device: m, DIMM: restDIMMs[i], Method: MatchPosition}
+				used[restDIMMs[i]] = true
+			}
+		}
+	}
+
+	var es []DIMMErrors
+	for _, m := range devs {
+		if e, ok := matched[m]; ok {
+			es = append(es, e)
+		} else {
+			es = append(es, DIMMErrors{Device: m})
+		}
+	}
+	for _, d := range ds {
+		if !used[d] {
+			es = append(es, DIMMErrors{DIMM: d})
+		}
+	}
+	return es
+}
+
+// GetDIMMErrors scans root and attaches its DIMMs to the memory devices of
+// the SMBIOS table
+func GetDIMMErrors(root string) ([]DIMMErrors, error) {
+	dimms, err := Scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return Correlate(godmi.GetMemoryDevices(), dimms), nil
+}
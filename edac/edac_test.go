@@ -0,0 +1,158 @@
+package edac
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs/sysfstest"
+)
+
+func TestScan(t *testing.T) {
+	root, err := ioutil.TempDir("", "edac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := sysfstest.WriteTree(root, map[string]string{
+		// Controllers and DIMMs sort by number, not by name
+		"mc10/dimm0/dimm_label":    "CPU1_DIMM_A1",
+		"mc10/dimm0/dimm_ue_count": "1",
+		"mc2/dimm10/dimm_location": "channel 1 slot 0",
+		"mc2/dimm10/size":          "16384",
+		"mc2/dimm2/dimm_location":  "channel 0 slot 1",
+		"mc2/dimm2/size":           "32768",
+		"mc2/dimm2/dimm_ce_count":  "3",
+		// Controller attributes and other entries are not DIMMs
+		"mc2/ce_count":        "3",
+		"mc2/dimmX/size":      "8192",
+		"mc2/rank0/size":      "8192",
+		"power/control":       "auto",
+		"mc2/dimm2/dimm_type": "Registered-DDR4",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ds, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		mc, index int
+		size, ce  uint64
+		location  []int
+	}{
+		{2, 2, 32768, 3, []int{0, 1}},
+		{2, 10, 16384, 0, []int{1, 0}},
+		{10, 0, 0, 0, nil},
+	}
+	if len(ds) != len(want) {
+		t.Fatalf("Scan: got %v", ds)
+	}
+	for i, w := range want {
+		d := ds[i]
+		if d.Controller != w.mc || d.Index != w.index || d.SizeMB != w.size || d.CECount != w.ce ||
+			len(d.LocationIndex) != len(w.location) {
+			t.Errorf("Scan %d: got %+v", i, d)
+			continue
+		}
+		for j := range w.location {
+			if d.LocationIndex[j] != w.location[j] {
+				t.Errorf("Scan %d: location %v, want %v", i, d.LocationIndex, w.location)
+			}
+		}
+	}
+	if ds[2].Label != "CPU1_DIMM_A1" || ds[2].UECount != 1 {
+		t.Errorf("Scan: got %+v", ds[2])
+	}
+	if _, err := Scan(filepath.Join(root, "missing")); err == nil {
+		t.Error("Scan: expected error for missing root")
+	}
+}
+
+func memoryDevice(bank, locator string, sizeMB uint16) *godmi.MemoryDevice {
+	return &godmi.MemoryDevice{BankLocator: bank, DeviceLocator: locator, Size: sizeMB}
+}
+
+func TestCorrelate(t *testing.T) {
+	a1 := memoryDevice("NODE 0", "DIMM_A1", 16384)
+	a2 := memoryDevice("NODE 0", "DIMM_A2", 8192)
+	b1 := memoryDevice("NODE 0", "DIMM_B1", 16384)
+	empty := memoryDevice("NODE 0", "DIMM_B2", 0)
+	dimms := []DIMM{
+		{Controller: 0, Index: 0, Label: "CPU0_DIMM_A1", SizeMB: 16384, CECount: 12},
+		// Blank labels, ordered by location rather than index
+		{Controller: 0, Index: 10, LocationIndex: []int{1, 0}, SizeMB: 16384, UECount: 1},
+		{Controller: 0, Index: 2, LocationIndex: []int{0, 1}, SizeMB: 8192, CECount: 3},
+		// A slot EDAC lists without a DIMM in it
+		{Controller: 0, Index: 3, LocationIndex: []int{1, 1}},
+	}
+	es := Correlate([]*godmi.MemoryDevice{a1, a2, b1, empty}, dimms)
+	want := []struct {
+		dev    *godmi.MemoryDevice
+		method MatchMethod
+		ce, ue uint64
+	}{
+		{a1, MatchLabel, 12, 0},
+		{a2, MatchPosition, 3, 0},
+		{b1, MatchPosition, 0, 1},
+	}
+	if len(es) != len(want) {
+		t.Fatalf("Correlate: got %v", es)
+	}
+	for i, w := range want {
+		e := es[i]
+		if e.Device != w.dev || e.Method != w.method || e.CECount() != w.ce || e.UECount() != w.ue {
+			t.Errorf("Correlate %d: got %v", i, e)
+		}
+	}
+}
+
+func TestCorrelateAmbiguousLabel(t *testing.T) {
+	// Both sockets have a DIMM_A1, so a label cannot tell them apart, even
+	// with a socket prefix
+	s0 := memoryDevice("P0", "DIMM_A1", 16384)
+	s1 := memoryDevice("P1", "DIMM_A1", 16384)
+	es := Correlate([]*godmi.MemoryDevice{s0, s1}, []DIMM{
+		{Controller: 0, Label: "DIMM_A1", SizeMB: 16384, CECount: 1},
+		{Controller: 1, Label: "P1 DIMM_A1", SizeMB: 16384, CECount: 2},
+	})
+	if len(es) != 2 || es[0].Device != s0 || es[0].Method != MatchPosition || es[0].CECount() != 1 ||
+		es[1].Device != s1 || es[1].Method != MatchPosition || es[1].CECount() != 2 {
+		t.Errorf("Correlate: got %v", es)
+	}
+}
+
+func TestCorrelateSizeMismatch(t *testing.T) {
+	// Sizes that disagree leave the DIMMs unmatched rather than guess
+	a1 := memoryDevice("", "DIMM_A1", 16384)
+	a2 := &godmi.MemoryDevice{DeviceLocator: "DIMM_A2", Size: 0x7FFF, ExtendedSize: 65536}
+	es := Correlate([]*godmi.MemoryDevice{a1, a2}, []DIMM{
+		{Index: 0, SizeMB: 16384},
+		{Index: 1, SizeMB: 32768},
+	})
+	if len(es) != 4 || es[0].DIMM != nil || es[1].DIMM != nil || es[2].Device != nil || es[3].Device != nil {
+		t.Errorf("Correlate: got %v", es)
+	}
+	if es[1].String() != "DIMM_A2: not reported by EDAC" {
+		t.Errorf("String: got %q", es[1])
+	}
+}
+
+func TestLabelMatches(t *testing.T) {
+	m := &godmi.MemoryDevice{DeviceLocator: "DIMM_A1", BankLocator: "BANK 0"}
+	for label, want := range map[string]bool{
+		"DIMM_A1":        true,
+		"dimm a1":        true,
+		"BANK 0 DIMM_A1": true,
+		"CPU0_DIMM_A1":   true,
+		"DIMM_A11":       false,
+		"XDIMM_A1":       false,
+		"":               false,
+	} {
+		if got := labelMatches(label, m); got != want {
+			t.Errorf("labelMatches(%q) = %v, want %v", label, got, want)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+// Package hwmon reads the live sensor values of the Linux hwmon subsystem
+// and attaches them to the SMBIOS voltage probes (type 26), cooling devices
+// (type 27), temperature probes (type 28) and electrical current probes
+// (type 29) they most likely measure.
+package hwmon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs"
+)
+
+// DefaultRoot is where the kernel lists hwmon devices
+const DefaultRoot = "/sys/class/hwmon"
+
+// Kind is the kind of quantity a sensor measures
+type Kind byte
+
+const (
+	KindVoltage Kind = 1 + iota
+	KindFan
+	KindTemperature
+	KindCurrent
+)
+
+func (k Kind) String() string {
+	kinds := [...]string{
+		"Voltage",
+		"Fan",
+		"Temperature",
+		"Current",
+	}
+	if k >= 1 && int(k) <= len(kinds) {
+		return kinds[k-1]
+	}
+	return godmi.OUT_OF_SPEC
+}
+
+// prefixes are the hwmon attribute prefixes of each kind
+var prefixes = map[string]Kind{
+	"in":   KindVoltage,
+	"fan":  KindFan,
+	"temp": KindTemperature,
+	"curr": KindCurrent,
+}
+
+// Sensor is a hwmon channel such as temp1. Values are in hwmon units: mV,
+// rpm, millidegree Celsius and mA.
+type Sensor struct {
+	// Chip is the name of the hwmon device, such as coretemp or nct6775
+	Chip  string
+	Dir   string
+	Kind  Kind
+	Index int
+	Label string
+	Input int64
+	// Min and Max are the limits set in the chip, HasMin and HasMax tell
+	// whether the chip has them at all
+	Min    int64
+	Max    int64
+	HasMin bool
+	HasMax bool
+}
+
+// Name returns the channel name, such as temp1
+func (s Sensor) Name() string {
+	for p, k := range prefixes {
+		if k == s.Kind {
+			return fmt.Sprintf("%s%d", p, s.Index)
+		}
+	}
+	return ""
+}
+
+func (s Sensor) String() string {
+	label := s.Label
+	if label == "" {
+		label = s.Name()
+	}
+	return fmt.Sprintf("%s/%s %q %d", s.Chip, s.Name(), label, s.Input)
+}
+
+// parseInput splits an attribute name such as temp1_input into its kind and
+// index
+func parseInput(name string) (Kind, int, bool) {
+	if !strings.HasSuffix(name, "_input") {
+		return 0, 0, false
+	}
+	name = strings.TrimSuffix(name, "_input")
+	for p, k := range prefixes {
+		if !strings.HasPrefix(name, p) {
+			continue
+		}
+		i, err := strconv.Atoi(name[len(p):])
+		if err != nil {
+			continue
+		}
+		return k, i, true
+	}
+	return 0, 0, false
+}
+
+func scanDir(chip, dir string) []Sensor {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var ss []Sensor
+	for _, fi := range fis {
+		k, i, ok := parseInput(fi.Name())
+		if !ok {
+			continue
+		}
+		s := Sensor{Chip: chip, Dir: dir, Kind: k, Index: i}
+		s.Input, ok = sysfs.ReadInt(dir, fi.Name())
+		if !ok {
+			continue
+		}
+		n := s.Name()
+		s.Label = sysfs.ReadString(dir, n+"_label")
+		s.Min, s.HasMin = sysfs.ReadInt(dir, n+"_min")
+		s.Max, s.HasMax = sysfs.ReadInt(dir, n+"_max")
+		ss = append(ss, s)
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Kind != ss[j].Kind {
+			return ss[i].Kind < ss[j].Kind
+		}
+		return ss[i].Index < ss[j].Index
+	})
+	return ss
+}
+
+// Scan reads every sensor of every hwmon device under root, which is
+// normally DefaultRoot. Older drivers keep their attributes in the device
+// directory, which is read as well.
+func Scan(root string) ([]Sensor, error) {
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var ss []Sensor
+	for _, fi := range fis {
+		dir := filepath.Join(root, fi.Name())
+		chip := sysfs.ReadString(dir, "name")
+		found := scanDir(chip, dir)
+		if len(found) == 0 {
+			found = scanDir(chip, filepath.Join(dir, "device"))
+		}
+		ss = append(ss, found...)
+	}
+	return ss, nil
+}
+
+// ReadingStatus tells how a live reading compares with the SMBIOS limits
+type ReadingStatus byte
+
+const (
+	ReadingStatusNoSensor ReadingStatus = 1 + iota
+	ReadingStatusOK
+	ReadingStatusOutOfRange
+)
+
+func (r ReadingStatus) String() string {
+	status := [...]string{
+		"No sensor",
+		"OK",
+		"Out of range",
+	}
+	if r >= 1 && int(r) <= len(status) {
+		return status[r-1]
+	}
+	return godmi.OUT_OF_SPEC
+}
+
+// Reading is a probe or cooling device together with the live value of the
+// hwmon sensor it was matched to. Value, Nominal, Minimum and Maximum are
+// godmi.Millivolts, godmi.RPM, godmi.Decicelsius or godmi.Milliamps
+// depending on Kind; Value is nil without a sensor and the limits are nil
+// when SMBIOS does not define them for the kind.
+type Reading struct {
+	Kind        Kind
+	Description string
+	// Structure is the *godmi.VoltageProbe, *godmi.CoolingDevice,
+	// *godmi.TemperatureProbe or *godmi.ElectricalCurrentProbe
+	Structure interface{}
+	Sensor    *Sensor
+	Value     fmt.Stringer
+	Nominal   fmt.Stringer
+	Minimum   fmt.Stringer
+	Maximum   fmt.Stringer
+	Status    ReadingStatus
+}
+
+func (r Reading) String() string {
+	s := fmt.Sprintf("%s %q: %s", r.Kind, r.Description, r.Status)
+	if r.Sensor == nil {
+		return s
+	}
+	s += fmt.Sprintf(", %s from %s/%s", r.Value, r.Sensor.Chip, r.Sensor.Name())
+	if r.Nominal != nil {
+		s += fmt.Sprintf(", nominal %s", r.Nominal)
+	}
+	if r.Minimum != nil && r.Maximum != nil {
+		s += fmt.Sprintf(", range %s to %s", r.Minimum, r.Maximum)
+	}
+	return s
+}
+
+// locationWords are words of sensor labels and chip names that point at a
+// probe location
+var locationWords = map[string][]string{
+	"Processor":        {"cpu", "core", "package", "tctl", "tdie", "coretemp", "k10temp", "vcore", "socket"},
+	"Processor Module": {"cpu", "core", "package", "vcore", "socket"},
+	"Memory Module":    {"dimm", "mem", "memory", "ddr", "vddq"},
+	"Disk":             {"disk", "drive", "hdd", "nvme", "sata"},
+	"Power Unit":       {"psu", "power", "pwr"},
+	"Motherboard":      {"board", "mb", "system", "sys", "pch", "chipset"},
+	"Add-in Card":      {"card", "gpu", "pcie"},
+	"Peripheral Bay":   {"bay"},
+}
+
+// words splits s into lower case words of letters and digits
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// wordMatches reports whether two words are the same, or one abbreviates
+// the other as in "sys" and "system" or "fan" and "fan2"
+func wordMatches(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len(a) < 3 || len(b) < 3 {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// score rates how well a sensor fits an SMBIOS description and location:
+// two points for every word of the description found in the sensor label,
+// one point for a word hinting at the location. An unlabeled channel, as
+// most fans are, is labeled by its kind and index, so that "System Fan 2"
+// finds fan2.
+func score(desc, location string, s Sensor) int {
+	name := s.Label
+	if name == "" {
+		name = fmt.Sprintf("%s %d", s.Kind, s.Index)
+	}
+	label := words(name + " " + s.Chip)
+	has := func(w string) bool {
+		for _, l := range label {
+			if wordMatches(w, l) {
+				return true
+			}
+		}
+		return false
+	}
+	n := 0
+	for _, w := range words(desc) {
+		if has(w) {
+			n += 2
+		}
+	}
+	for _, w := range locationWords[location] {
+		if has(w) {
+			n++
+			break
+		}
+	}
+	return n
+}
+
+type candidate struct {
+	reading int
+	sensor  int
+	score   int
+}
+
+// Correlate matches the probes and cooling devices to the sensors and
+// reports their readings.
+//
+// Sensors are matched by kind, then by how many words of the SMBIOS
+// description appear in the hwmon label and chip name, with a bonus for
+// words that hint at the probe location, such as "cpu" for a processor
+// probe. A cooling device takes the location of the temperature probe it is
+// tied to. The best scoring pairs are taken first and every sensor is used
+// at most once; a probe without any matching word gets no sensor.
+func Correlate(vs []*godmi.VoltageProbe, cds []*godmi.CoolingDevice, ts []*godmi.TemperatureProbe,
+	cs []*godmi.ElectricalCurrentProbe, sensors []Sensor) []Reading {
+	var rs []Reading
+	var locations []string
+	probes := make(map[uint16]*godmi.TemperatureProbe)
+	for _, t := range ts {
+		probes[uint16(t.Handle)] = t
+	}
+	for _, v := range vs {
+		rs = append(rs, Reading{
+			Kind:        KindVoltage,
+			Description: v.Description,
+			Structure:   v,
+			Nominal:     v.NominalValue,
+			Minimum:     v.MinimumValue,
+			Maximum:     v.MaximumValue,
+		})
+		locations = append(locations, v.LocationAndStatus.Location.String())
+	}
+	for _, c := range cds {
+		rs = append(rs, Reading{
+			Kind:        KindFan,
+			Description: c.Description,
+			Structure:   c,
+			Nominal:     c.NominalSpeed,
+		})
+		var location string
+		if t, ok := probes[c.TemperatureProbeHandle]; ok {
+			location = t.LocationAndStatus.Location.String()
+		}
+		locations = append(locations, location)
+	}
+	for _, t := range ts {
+		rs = append(rs, Reading{
+			Kind:        KindTemperature,
+			Description: t.Description,
+			Structure:   t,
+			Nominal:     t.NominalValue,
+			Minimum:     t.MinimumValue,
+			Maximum:     t.MaximumValue,
+		})
+		locations = append(locations, t.LocationAndStatus.Location.String())
+	}
+	for _, c := range cs {
+		rs = append(rs, Reading{
+			Kind:        KindCurrent,
+			Description: c.Description,
+			Structure:   c,
+			Nominal:     c.NominalValue,
+			Minimum:     c.MinimumValue,
+			Maximum:     c.MaximumValue,
+		})
+		locations = append(locations, c.LocationAndStatus.Location.String())
+	}
+
+	var cands []candidate
+	for i, r := range rs {
+		for j, s := range sensors {
+			if s.Kind != r.Kind {
+				continue
+			}
+			if n := score(r.Description, locations[i], s); n > 0 {
+				cands = append(cands, candidate{i, j, n})
+			}
+		}
+	}
+	sort.SliceStable(cands, func(i, j int) bool {
+		return cands[i].score > cands[j].score
+	})
+	used := make(map[int]bool)
+	for _, c := range cands {
+		if used[c.sensor] || rs[c.reading].Sensor != nil {
+			continue
+		}
+		used[c.sensor] = true
+		rs[c.reading].Sensor = &sensors[c.sensor]
+	}
+
+	for i := range rs {
+		rs[i].evaluate()
+	}
+	return rs
+}
+
+// clamp16 limits a hwmon value to the range of the SMBIOS fields, which
+// keep 0x8000 for unknown values
+func clamp16(i int64) int16 {
+	switch {
+	case i > 0x7FFF:
+		return 0x7FFF
+	case i < -0x7FFF:
+		return -0x7FFF
+	}
+	return int16(i)
+}
+
+// evaluate converts the sensor value to SMBIOS units and checks it against
+// the SMBIOS limits, or for fans against the chip minimum
+func (r *Reading) evaluate() {
+	s := r.Sensor
+	if s == nil {
+		r.Status = ReadingStatusNoSensor
+		return
+	}
+	in := true
+	switch st := r.Structure.(type) {
+	case *godmi.VoltageProbe:
+		v := godmi.Millivolts(clamp16(s.Input))
+		r.Value = v
+		in = st.InRange(v)
+	case *godmi.CoolingDevice:
+		r.Value = godmi.RPM(s.Input)
+		in = s.Input > 0 && (!s.HasMin || s.Input >= s.Min)
+	case *godmi.TemperatureProbe:
+		v := godmi.Decicelsius(clamp16(s.Input / 100))
+		r.Value = v
+		in = st.InRange(v)
+	case *godmi.ElectricalCurrentProbe:
+		v := godmi.Milliamps(clamp16(s.Input))
+		r.Value = v
+		in = st.InRange(v)
+	}
+	if in {
+		r.Status = ReadingStatusOK
+	} else {
+		r.Status = ReadingStatusOutOfRange
+	}
+}
+
+// GetReadings scans root and attaches its sensors to the probes and cooling
+// devices of the SMBIOS table
+func GetReadings(root string) ([]Reading, error) {
+	sensors, err := Scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return Correlate(godmi.GetVoltageProbes(), godmi.GetCoolingDevices(), godmi.GetTemperatureProbes(),
+		godmi.GetElectricalCurrentProbes(), sensors), nil
+}
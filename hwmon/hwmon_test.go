@@ -0,0 +1,209 @@
+package hwmon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs/sysfstest"
+)
+
+func TestScan(t *testing.T) {
+	root, err := ioutil.TempDir("", "hwmon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := sysfstest.WriteTree(root, map[string]string{
+		"hwmon0/name":        "nct6775",
+		"hwmon0/fan10_input": "0",
+		"hwmon0/fan2_input":  "1450",
+		"hwmon0/fan2_min":    "300",
+		"hwmon0/in0_input":   "1184",
+		"hwmon0/temp7_input": "-5000",
+		// Limits and alarms are not channels, and a channel whose input
+		// does not read as a number is dropped
+		"hwmon0/temp7_crit":       "100000",
+		"hwmon0/intrusion0_alarm": "0",
+		"hwmon0/temp8_input":      "",
+		"hwmon0/tempX_input":      "1",
+		// Old drivers keep their attributes under device
+		"hwmon1/name":               "ipmi",
+		"hwmon1/device/curr1_input": "2300",
+		"hwmon1/device/curr1_label": "PSU1 Current",
+		"hwmon1/device/curr1_max":   "10000",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ss, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		chip, name     string
+		input          int64
+		hasMin, hasMax bool
+	}{
+		{"nct6775", "in0", 1184, false, false},
+		{"nct6775", "fan2", 1450, true, false},
+		{"nct6775", "fan10", 0, false, false},
+		{"nct6775", "temp7", -5000, false, false},
+		{"ipmi", "curr1", 2300, false, true},
+	}
+	if len(ss) != len(want) {
+		t.Fatalf("Scan: got %v", ss)
+	}
+	for i, w := range want {
+		s := ss[i]
+		if s.Chip != w.chip || s.Name() != w.name || s.Input != w.input || s.HasMin != w.hasMin || s.HasMax != w.hasMax {
+			t.Errorf("Scan %d: got %+v", i, s)
+		}
+	}
+	if c := ss[4]; c.Label != "PSU1 Current" || c.Max != 10000 || c.Dir != filepath.Join(root, "hwmon1", "device") {
+		t.Errorf("Scan device dir: got %+v", c)
+	}
+	if _, err := Scan(filepath.Join(root, "missing")); err == nil {
+		t.Error("Scan: expected error for missing root")
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	sensors := []Sensor{
+		{Chip: "nct6775", Kind: KindVoltage, Index: 0, Label: "Vcore", Input: 1184},
+		{Chip: "coretemp", Kind: KindTemperature, Index: 1, Label: "Package id 0", Input: 92000},
+		{Chip: "coretemp", Kind: KindTemperature, Index: 2, Label: "Core 0", Input: 45000},
+		{Chip: "nct6775", Kind: KindTemperature, Index: 7, Label: "SYSTIN", Input: 38000},
+		{Chip: "nct6775", Kind: KindFan, Index: 1, Label: "CPU_FAN", Input: 250, Min: 300, HasMin: true},
+		{Chip: "nct6775", Kind: KindFan, Index: 2, Label: "SYS_FAN2", Input: 0},
+		{Chip: "ipmi", Kind: KindCurrent, Index: 1, Label: "PSU1 Current", Input: 2300},
+	}
+	vs := []*godmi.VoltageProbe{{
+		Description:       "CPU Vcore",
+		LocationAndStatus: godmi.VoltageProbeLocationAndStatus{Location: godmi.VoltageProbeLocationProcessor},
+		MinimumValue:      900,
+		MaximumValue:      1500,
+	}}
+	cds := []*godmi.CoolingDevice{
+		{Description: "CPU Fan"},
+		{Description: "System Fan 2"},
+		// No sensor shares a word with it
+		{Description: "Rear Blower"},
+	}
+	ts := []*godmi.TemperatureProbe{
+		// Both CPU probes want the package sensor, the better match wins
+		{
+			Description:       "CPU Package",
+			LocationAndStatus: godmi.TemperatureProbeLocationAndStatus{Location: godmi.TemperatureProbeLocationProcessor},
+			MinimumValue:      0,
+			MaximumValue:      900,
+		},
+		{
+			Description:       "CPU Core",
+			LocationAndStatus: godmi.TemperatureProbeLocationAndStatus{Location: godmi.TemperatureProbeLocationProcessor},
+			MinimumValue:      -0x8000,
+			MaximumValue:      -0x8000,
+		},
+		// The location word alone matches the board sensor
+		{
+			Description:       "Ambient",
+			LocationAndStatus: godmi.TemperatureProbeLocationAndStatus{Location: godmi.TemperatureProbeLocationMotherboard},
+			MinimumValue:      -0x8000,
+			MaximumValue:      -0x8000,
+		},
+	}
+	cs := []*godmi.ElectricalCurrentProbe{{Description: "PSU1 Current", MinimumValue: 0, MaximumValue: 2000}}
+	rs := Correlate(vs, cds, ts, cs, sensors)
+	want := []struct {
+		sensor string
+		value  string
+		status ReadingStatus
+	}{
+		{"in0", "1.184 V", ReadingStatusOK},
+		{"fan1", "250 rpm", ReadingStatusOutOfRange},
+		{"fan2", "0 rpm", ReadingStatusOutOfRange},
+		{"", "", ReadingStatusNoSensor},
+		{"temp1", "92.0 deg C", ReadingStatusOutOfRange},
+		{"temp2", "45.0 deg C", ReadingStatusOK},
+		{"temp7", "38.0 deg C", ReadingStatusOK},
+		{"curr1", "2.300 A", ReadingStatusOutOfRange},
+	}
+	if len(rs) != len(want) {
+		t.Fatalf("Correlate: got %v", rs)
+	}
+	for i, w := range want {
+		r := rs[i]
+		var name, value string
+		if r.Sensor != nil {
+			name = r.Sensor.Name()
+			value = r.Value.String()
+		}
+		if name != w.sensor || value != w.value || r.Status != w.status {
+			t.Errorf("Correlate %d: got %v, want %v", i, r, w)
+		}
+	}
+}
+
+func TestClamp16(t *testing.T) {
+	// 0x8000 is unknown in SMBIOS, so a reading must never become it
+	for in, want := range map[int64]int16{
+		1184:     1184,
+		-40:      -40,
+		1 << 20:  0x7FFF,
+		-1 << 20: -0x7FFF,
+		-0x8000:  -0x7FFF,
+	} {
+		if got := clamp16(in); got != want {
+			t.Errorf("clamp16(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestCorrelateUnlabeledFans(t *testing.T) {
+	sensors := []Sensor{
+		{Chip: "nct6775", Kind: KindFan, Index: 1, Input: 1200},
+		{Chip: "nct6775", Kind: KindFan, Index: 2, Input: 900},
+		{Chip: "nct6775", Kind: KindFan, Index: 3, Input: 0},
+	}
+	cds := []*godmi.CoolingDevice{
+		{Description: "CPU Fan"},
+		{Description: "System Fan 3"},
+		{Description: "Rear Blower"},
+	}
+	// The index decides for System Fan 3, CPU Fan takes the first fan left
+	want := []string{"fan1", "fan3", ""}
+	rs := Correlate(nil, cds, nil, nil, sensors)
+	for i, w := range want {
+		var name string
+		if rs[i].Sensor != nil {
+			name = rs[i].Sensor.Name()
+		}
+		if name != w {
+			t.Errorf("Correlate %d: got %v, want %s", i, rs[i], w)
+		}
+	}
+
+	rs = Correlate(nil, cds[:1], nil, nil, sensors[1:2])
+	if rs[0].Sensor == nil || rs[0].Sensor.Name() != "fan2" || rs[0].Status != ReadingStatusOK {
+		t.Errorf("Correlate with a single fan: got %v", rs[0])
+	}
+}
+
+func TestCorrelateFanLocation(t *testing.T) {
+	sensors := []Sensor{
+		{Chip: "nct6775", Kind: KindFan, Index: 1, Label: "SYS_FAN", Input: 900},
+		{Chip: "nct6775", Kind: KindFan, Index: 2, Label: "CPU_FAN", Input: 1200},
+	}
+	cpu := &godmi.TemperatureProbe{
+		Description:       "Package",
+		LocationAndStatus: godmi.TemperatureProbeLocationAndStatus{Location: godmi.TemperatureProbeLocationProcessor},
+	}
+	cpu.Handle = 0x2800
+	// The fan is placed by the probe it is tied to
+	cds := []*godmi.CoolingDevice{{Description: "Fan", TemperatureProbeHandle: 0x2800}}
+	rs := Correlate(nil, cds, []*godmi.TemperatureProbe{cpu}, nil, sensors)
+	if rs[0].Sensor == nil || rs[0].Sensor.Name() != "fan2" {
+		t.Errorf("Correlate: got %v", rs[0])
+	}
+}
@@ -0,0 +1,50 @@
+// Package sysfs reads the attributes of sysfs objects for the packages that
+// tie them to SMBIOS structures.
+package sysfs
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReadString returns the attribute name of dir without its trailing
+// newline, or "" if it cannot be read
+func ReadString(dir, name string) string {
+	b, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// ReadUint returns a decimal attribute, or 0 if it is missing or malformed.
+// The kernel uses 0 for unknown in the counters and sizes read this way.
+func ReadUint(dir, name string) uint64 {
+	u, err := strconv.ParseUint(ReadString(dir, name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return u
+}
+
+// ReadHex returns a hexadecimal attribute such as a PCI ID, with or without
+// its 0x prefix, or 0 if it is missing or malformed
+func ReadHex(dir, name string) uint64 {
+	u, err := strconv.ParseUint(strings.TrimPrefix(ReadString(dir, name), "0x"), 16, 64)
+	if err != nil {
+		return 0
+	}
+	return u
+}
+
+// ReadInt returns a signed decimal attribute. As any value, 0 included, is
+// a valid reading, it also reports whether the attribute could be read.
+func ReadInt(dir, name string) (int64, bool) {
+	i, err := strconv.ParseInt(ReadString(dir, name), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return i, true
+}
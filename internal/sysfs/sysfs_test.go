@@ -0,0 +1,63 @@
+package sysfs
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ochapman/godmi/internal/sysfs/sysfstest"
+)
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := sysfstest.WriteTree(dir, map[string]string{
+		"label":    "  DIMM_A1 ",
+		"size":     "8192",
+		"vendor":   "0x8086",
+		"class":    "060400",
+		"temp":     "-5000",
+		"empty":    "",
+		"garbage":  "N/A",
+		"overflow": "18446744073709551616",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if s := ReadString(dir, "label"); s != "DIMM_A1" {
+		t.Errorf("ReadString: got %q", s)
+	}
+	if s := ReadString(dir, "missing"); s != "" {
+		t.Errorf("ReadString missing: got %q", s)
+	}
+	for name, want := range map[string]uint64{
+		"size": 8192, "temp": 0, "garbage": 0, "overflow": 0, "missing": 0,
+	} {
+		if u := ReadUint(dir, name); u != want {
+			t.Errorf("ReadUint(%s) = %d, want %d", name, u, want)
+		}
+	}
+	for name, want := range map[string]uint64{
+		"vendor": 0x8086, "class": 0x060400, "garbage": 0, "missing": 0,
+	} {
+		if u := ReadHex(dir, name); u != want {
+			t.Errorf("ReadHex(%s) = %#x, want %#x", name, u, want)
+		}
+	}
+	for name, want := range map[string]struct {
+		i  int64
+		ok bool
+	}{
+		"temp":    {-5000, true},
+		"size":    {8192, true},
+		"empty":   {0, false},
+		"garbage": {0, false},
+		"missing": {0, false},
+	} {
+		if i, ok := ReadInt(dir, name); i != want.i || ok != want.ok {
+			t.Errorf("ReadInt(%s) = %d, %v, want %d, %v", name, i, ok, want.i, want.ok)
+		}
+	}
+}
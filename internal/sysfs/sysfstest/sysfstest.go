@@ -0,0 +1,24 @@
+// Package sysfstest builds fake sysfs trees for the tests of the packages
+// that read sysfs.
+package sysfstest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteTree creates files under root, each holding its value and a newline
+// as sysfs attributes do. Names may contain directories.
+func WriteTree(root string, files map[string]string) error {
+	for name, v := range files {
+		f := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(f), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(f, []byte(v+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package pci
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ochapman/godmi"
+)
+
+// InterfaceName holds the names a network device gets from firmware data.
+// An empty name means the scheme would not name the device from SMBIOS and
+// falls back to its next policy, such as the path based enp3s0f0.
+type InterfaceName struct {
+	Address Address
+	// Systemd is the udev name, eno<index> or ens<slot>[f<function>]
+	Systemd string
+	// Biosdevname is the biosdevname name, em<index> or p<slot>p<port>
+	Biosdevname string
+}
+
+func (n InterfaceName) String() string {
+	return fmt.Sprintf("%s systemd=%s biosdevname=%s", n.Address, n.Systemd, n.Biosdevname)
+}
+
+func multifunction(d Device, devs []Device) bool {
+	for _, o := range devs {
+		if o.Address.Segment == d.Address.Segment && o.Address.Bus == d.Address.Bus &&
+			o.Address.Device == d.Address.Device && o.Address.Function != d.Address.Function {
+			return true
+		}
+	}
+	return false
+}
+
+// InterfaceNames computes the systemd and biosdevname names of the network
+// devices devs from the on board devices (type 41) and system slots
+// (type 9). The devices only need their Address set; when Parents are set
+// too, devices behind a slot's bridge are matched to that slot.
+//
+// On board devices take precedence over slots, as in both naming tools. The
+// slot number used is the SMBIOS slot ID, which the kernel normally exposes
+// under /sys/bus/pci/slots as well.
+func InterfaceNames(slots []*godmi.SystemSlot, obds []*godmi.OnBoardDevicesExtendedInformation, devs []Device) []InterfaceName {
+	names := make(map[Address]*InterfaceName)
+	var ns []*InterfaceName
+	for _, d := range devs {
+		n := &InterfaceName{Address: d.Address}
+		names[d.Address] = n
+		ns = append(ns, n)
+	}
+
+	for _, o := range obds {
+		if o.BusAddress() == "" {
+			continue
+		}
+		n, ok := names[newAddress(o.SegmentGroupNumber, o.BusNumber, o.DeviceFunctionNumber)]
+		if !ok {
+			continue
+		}
+		// Both tools treat instance 0 as unset
+		if o.DeviceTypeInstance == 0 {
+			continue
+		}
+		n.Systemd = fmt.Sprintf("eno%d", o.DeviceTypeInstance)
+		n.Biosdevname = fmt.Sprintf("em%d", o.DeviceTypeInstance)
+	}
+
+	for _, sd := range MatchSystemSlots(slots, devs) {
+		sorted := append([]Device(nil), sd.Devices...)
+		sort.Slice(sorted, func(i, j int) bool {
+			a, b := sorted[i].Address, sorted[j].Address
+			if a.Segment != b.Segment {
+				return a.Segment < b.Segment
+			}
+			if a.Bus != b.Bus {
+				return a.Bus < b.Bus
+			}
+			if a.Device != b.Device {
+				return a.Device < b.Device
+			}
+			return a.Function < b.Function
+		})
+		port := 0
+		for _, d := range sorted {
+			n, ok := names[d.Address]
+			if !ok || d.IsBridge() {
+				continue
+			}
+			port++
+			if n.Systemd == "" {
+				n.Systemd = fmt.Sprintf("ens%d", sd.Slot.ID)
+				if d.Address.Function > 0 || multifunction(d, devs) {
+					n.Systemd += fmt.Sprintf("f%d", d.Address.Function)
+				}
+			}
+			if n.Biosdevname == "" {
+				n.Biosdevname = fmt.Sprintf("p%dp%d", sd.Slot.ID, port)
+			}
+		}
+	}
+
+	var r []InterfaceName
+	for _, n := range ns {
+		r = append(r, *n)
+	}
+	return r
+}
+
+// AddressDevices turns a list of addresses into devices for InterfaceNames
+func AddressDevices(addrs []Address) []Device {
+	var ds []Device
+	for _, a := range addrs {
+		ds = append(ds, Device{Address: a})
+	}
+	return ds
+}
+
+// GetInterfaceNames computes the names of the devices at addrs from the
+// SMBIOS table
+func GetInterfaceNames(addrs []Address) []InterfaceName {
+	return InterfaceNames(godmi.GetSystemSlots(), godmi.GetOnBoardDevicesExtendedInformations(), AddressDevices(addrs))
+}
@@ -0,0 +1,323 @@
+// Package pci reads PCI devices from sysfs and attaches them to the SMBIOS
+// system slots (type 9) and on board devices (type 41) they sit behind.
+package pci
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs"
+)
+
+// DefaultRoot is where the kernel lists PCI devices
+const DefaultRoot = "/sys/bus/pci/devices"
+
+// Address is a PCI segment:bus:device.function address
+type Address struct {
+	Segment  uint16
+	Bus      byte
+	Device   byte
+	Function byte
+}
+
+func (a Address) String() string {
+	return fmt.Sprintf("%04x:%02x:%02x.%x", a.Segment, a.Bus, a.Device, a.Function)
+}
+
+// ParseAddress parses an address such as 0000:03:00.1
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	var seg, bus, dev, fn uint
+	n, err := fmt.Sscanf(s, "%04x:%02x:%02x.%1x", &seg, &bus, &dev, &fn)
+	if err != nil || n != 4 || len(s) != 12 || dev > 0x1F || fn > 0x7 {
+		return a, fmt.Errorf("pci: invalid address %q", s)
+	}
+	a.Segment = uint16(seg)
+	a.Bus = byte(bus)
+	a.Device = byte(dev)
+	a.Function = byte(fn)
+	return a, nil
+}
+
+// newAddress builds an address from the SMBIOS segment, bus and
+// device/function fields
+func newAddress(segment uint16, bus byte, devfn byte) Address {
+	return Address{
+		Segment:  segment,
+		Bus:      bus,
+		Device:   devfn >> 3,
+		Function: devfn & 0x7,
+	}
+}
+
+// Device is a PCI device as seen in sysfs
+type Device struct {
+	Address Address
+	// Parents are the upstream bridges of the device, root port first
+	Parents          []Address
+	VendorID         uint16
+	DeviceID         uint16
+	Class            uint32
+	Driver           string
+	CurrentLinkSpeed string
+	CurrentLinkWidth int
+	MaxLinkSpeed     string
+	MaxLinkWidth     int
+}
+
+// IsBridge reports whether the device is a PCI-to-PCI bridge
+func (d Device) IsBridge() bool {
+	return d.Class>>8 == 0x0604
+}
+
+// CurrentLinkGeneration returns the PCI Express generation the link runs at
+func (d Device) CurrentLinkGeneration() int {
+	return LinkGeneration(d.CurrentLinkSpeed)
+}
+
+// MaxLinkGeneration returns the highest PCI Express generation of the device
+func (d Device) MaxLinkGeneration() int {
+	return LinkGeneration(d.MaxLinkSpeed)
+}
+
+func (d Device) String() string {
+	s := fmt.Sprintf("%s %04x:%04x", d.Address, d.VendorID, d.DeviceID)
+	if d.Driver != "" {
+		s += " " + d.Driver
+	}
+	if d.CurrentLinkWidth > 0 {
+		s += fmt.Sprintf(" x%d Gen%d", d.CurrentLinkWidth, d.CurrentLinkGeneration())
+	}
+	return s
+}
+
+// LinkGeneration converts a sysfs link speed such as "8.0 GT/s PCIe" to a
+// PCI Express generation, or 0 if the speed is unknown
+func LinkGeneration(speed string) int {
+	f := strings.Fields(speed)
+	if len(f) == 0 {
+		return 0
+	}
+	gts, err := strconv.ParseFloat(f[0], 64)
+	if err != nil {
+		return 0
+	}
+	switch gts {
+	case 2.5:
+		return 1
+	case 5:
+		return 2
+	case 8:
+		return 3
+	case 16:
+		return 4
+	case 32:
+		return 5
+	case 64:
+		return 6
+	}
+	return 0
+}
+
+func newDevice(root string, name string) (Device, error) {
+	var d Device
+	a, err := ParseAddress(name)
+	if err != nil {
+		return d, err
+	}
+	dir := filepath.Join(root, name)
+	d.Address = a
+	if p, err := filepath.EvalSymlinks(dir); err == nil {
+		for _, c := range strings.Split(filepath.Dir(p), string(os.PathSeparator)) {
+			if pa, err := ParseAddress(c); err == nil {
+				d.Parents = append(d.Parents, pa)
+			}
+		}
+	}
+	d.VendorID = uint16(sysfs.ReadHex(dir, "vendor"))
+	d.DeviceID = uint16(sysfs.ReadHex(dir, "device"))
+	d.Class = uint32(sysfs.ReadHex(dir, "class"))
+	if p, err := os.Readlink(filepath.Join(dir, "driver")); err == nil {
+		d.Driver = filepath.Base(p)
+	}
+	d.CurrentLinkSpeed = sysfs.ReadString(dir, "current_link_speed")
+	w, _ := sysfs.ReadInt(dir, "current_link_width")
+	d.CurrentLinkWidth = int(w)
+	d.MaxLinkSpeed = sysfs.ReadString(dir, "max_link_speed")
+	w, _ = sysfs.ReadInt(dir, "max_link_width")
+	d.MaxLinkWidth = int(w)
+	return d, nil
+}
+
+// Scan reads every PCI device under root, which is normally DefaultRoot
+func Scan(root string) ([]Device, error) {
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var ds []Device
+	for _, fi := range fis {
+		d, err := newDevice(root, fi.Name())
+		if err != nil {
+			continue
+		}
+		ds = append(ds, d)
+	}
+	return ds, nil
+}
+
+// behind returns the devices at address a. If a is a bridge, the devices
+// downstream of it are returned instead. An endpoint address stands for
+// every function of its device, but a bridge only for itself, so that
+// sibling root ports such as 00:1c.0 and 00:1c.4 are told apart.
+func behind(a Address, devs []Device) []Device {
+	for _, d := range devs {
+		if d.Address != a || !d.IsBridge() {
+			continue
+		}
+		var below []Device
+		for _, c := range devs {
+			if hasParent(c, a) {
+				below = append(below, c)
+			}
+		}
+		if len(below) > 0 {
+			return below
+		}
+		return []Device{d}
+	}
+	var fns []Device
+	for _, d := range devs {
+		if d.Address.Segment == a.Segment && d.Address.Bus == a.Bus &&
+			d.Address.Device == a.Device && !d.IsBridge() {
+			fns = append(fns, d)
+		}
+	}
+	return fns
+}
+
+func hasParent(d Device, a Address) bool {
+	for _, p := range d.Parents {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
+
+// SlotDevices is a system slot together with the PCI devices in it
+type SlotDevices struct {
+	Slot    *godmi.SystemSlot
+	Devices []Device
+}
+
+// Degraded returns the devices whose link runs narrower or slower than both
+// the slot and the device support
+func (s SlotDevices) Degraded() []Device {
+	var ds []Device
+	lanes := s.Slot.DataBusWidth.Lanes()
+	gen := s.Slot.PCIExpressGeneration()
+	for _, d := range s.Devices {
+		if d.CurrentLinkWidth == 0 {
+			continue
+		}
+		wantLanes := lanes
+		if d.MaxLinkWidth > 0 && (wantLanes == 0 || d.MaxLinkWidth < wantLanes) {
+			wantLanes = d.MaxLinkWidth
+		}
+		wantGen := gen
+		if mg := d.MaxLinkGeneration(); mg > 0 && (wantGen == 0 || mg < wantGen) {
+			wantGen = mg
+		}
+		if d.CurrentLinkWidth < wantLanes || d.CurrentLinkGeneration() < wantGen {
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
+
+func (s SlotDevices) String() string {
+	str := fmt.Sprintf("%s (%s)", s.Slot.Designation, s.Slot.Type)
+	if lanes := s.Slot.DataBusWidth.Lanes(); lanes > 0 {
+		str += fmt.Sprintf(" x%d", lanes)
+	}
+	if gen := s.Slot.PCIExpressGeneration(); gen > 0 {
+		str += fmt.Sprintf(" Gen%d", gen)
+	}
+	for _, d := range s.Devices {
+		str += "\n\t" + d.String()
+	}
+	return str
+}
+
+// OnBoardDevices is an on board device together with its PCI devices
+type OnBoardDevices struct {
+	Device  *godmi.OnBoardDevicesExtendedInformation
+	Devices []Device
+}
+
+func (o OnBoardDevices) String() string {
+	str := fmt.Sprintf("%s (%s)", o.Device.ReferenceDesignation, o.Device.DeviceType)
+	for _, d := range o.Devices {
+		str += "\n\t" + d.String()
+	}
+	return str
+}
+
+// MatchSystemSlots attaches the PCI devices to the slots they sit in,
+// including the peer devices of bifurcated slots
+func MatchSystemSlots(slots []*godmi.SystemSlot, devs []Device) []SlotDevices {
+	var sds []SlotDevices
+	for _, s := range slots {
+		sd := SlotDevices{Slot: s}
+		if s.BusAddress() != "" {
+			a := newAddress(uint16(s.SegmentGroupNumber), byte(s.BusNumber), byte(s.DeviceFunctionNumber))
+			sd.Devices = append(sd.Devices, behind(a, devs)...)
+		}
+		for _, p := range s.PeerDevices {
+			a := newAddress(p.SegmentGroupNumber, p.BusNumber, p.DeviceFunctionNumber)
+			sd.Devices = append(sd.Devices, behind(a, devs)...)
+		}
+		sds = append(sds, sd)
+	}
+	return sds
+}
+
+// MatchOnBoardDevices attaches the PCI devices to the on board devices
+func MatchOnBoardDevices(obds []*godmi.OnBoardDevicesExtendedInformation, devs []Device) []OnBoardDevices {
+	var ods []OnBoardDevices
+	for _, o := range obds {
+		od := OnBoardDevices{Device: o}
+		if o.BusAddress() != "" {
+			a := newAddress(o.SegmentGroupNumber, o.BusNumber, o.DeviceFunctionNumber)
+			od.Devices = behind(a, devs)
+		}
+		ods = append(ods, od)
+	}
+	return ods
+}
+
+// GetSystemSlotDevices scans root and attaches its devices to the system
+// slots of the SMBIOS table
+func GetSystemSlotDevices(root string) ([]SlotDevices, error) {
+	devs, err := Scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return MatchSystemSlots(godmi.GetSystemSlots(), devs), nil
+}
+
+// GetOnBoardDevices scans root and attaches its devices to the on board
+// devices of the SMBIOS table
+func GetOnBoardDevices(root string) ([]OnBoardDevices, error) {
+	devs, err := Scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return MatchOnBoardDevices(godmi.GetOnBoardDevicesExtendedInformations(), devs), nil
+}
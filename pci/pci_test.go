@@ -0,0 +1,231 @@
+package pci
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs/sysfstest"
+)
+
+// fakeDevice creates a device directory under root/devices and links it
+// from root/bus/pci/devices
+func fakeDevice(t *testing.T, root string, path string, attrs map[string]string, driver string) {
+	dir := filepath.Join(root, "devices", path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := sysfstest.WriteTree(dir, attrs); err != nil {
+		t.Fatal(err)
+	}
+	if driver != "" {
+		ddir := filepath.Join(root, "bus", "pci", "drivers", driver)
+		os.MkdirAll(ddir, 0755)
+		if err := os.Symlink(ddir, filepath.Join(dir, "driver")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bus := filepath.Join(root, "bus", "pci", "devices")
+	os.MkdirAll(bus, 0755)
+	if err := os.Symlink(dir, filepath.Join(bus, filepath.Base(path))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func fakeSysfs(t *testing.T, maxSpeed, maxWidth string) string {
+	root, err := ioutil.TempDir("", "pci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeDevice(t, root, "pci0000:00/0000:00:01.0", map[string]string{
+		"vendor": "0x8086",
+		"device": "0x2030",
+		"class":  "0x060400",
+	}, "pcieport")
+	for _, fn := range []string{"0", "1"} {
+		fakeDevice(t, root, "pci0000:00/0000:00:01.0/0000:03:00."+fn, map[string]string{
+			"vendor":             "0x8086",
+			"device":             "0x1572",
+			"class":              "0x020000",
+			"current_link_speed": "8.0 GT/s PCIe",
+			"current_link_width": "8",
+			"max_link_speed":     maxSpeed,
+			"max_link_width":     maxWidth,
+		}, "i40e")
+	}
+	fakeDevice(t, root, "pci0000:00/0000:00:1f.6", map[string]string{
+		"vendor": "0x8086",
+		"device": "0x15b8",
+		"class":  "0x020000",
+	}, "e1000e")
+	return filepath.Join(root, "bus", "pci", "devices")
+}
+
+func TestParseAddress(t *testing.T) {
+	a, err := ParseAddress("0001:3a:1f.7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != (Address{Segment: 1, Bus: 0x3a, Device: 0x1f, Function: 7}) || a.String() != "0001:3a:1f.7" {
+		t.Errorf("ParseAddress: got %#v", a)
+	}
+	for _, s := range []string{"pci0000:00", "0000:00:20.0", "0000:00:01.0x"} {
+		if _, err := ParseAddress(s); err == nil {
+			t.Errorf("ParseAddress(%q): expected error", s)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	root := fakeSysfs(t, "8.0 GT/s PCIe", "8")
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(filepath.Dir(root))))
+	devs, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devs) != 4 {
+		t.Fatalf("Scan: got %d devices, want 4", len(devs))
+	}
+	var d Device
+	for _, dev := range devs {
+		if dev.Address.String() == "0000:03:00.0" {
+			d = dev
+		}
+	}
+	if d.Address.String() != "0000:03:00.0" || d.VendorID != 0x8086 || d.DeviceID != 0x1572 ||
+		d.Driver != "i40e" || d.CurrentLinkWidth != 8 || d.CurrentLinkGeneration() != 3 {
+		t.Errorf("Scan: got %#v", d)
+	}
+	if len(d.Parents) != 1 || d.Parents[0].String() != "0000:00:01.0" {
+		t.Errorf("Scan: parents %v", d.Parents)
+	}
+}
+
+func TestMatchSystemSlots(t *testing.T) {
+	slot := &godmi.SystemSlot{
+		Designation:          "SLOT1",
+		Type:                 godmi.SystemSlotTypePCIExpressGen4x16,
+		DataBusWidth:         godmi.SystemSlotDataBusWidth16xorx16,
+		SegmentGroupNumber:   0,
+		BusNumber:            0,
+		DeviceFunctionNumber: 0x01 << 3,
+	}
+	for _, tc := range []struct {
+		maxSpeed, maxWidth string
+		degraded           int
+	}{
+		{"8.0 GT/s PCIe", "8", 0},
+		{"16.0 GT/s PCIe", "16", 2},
+	} {
+		root := fakeSysfs(t, tc.maxSpeed, tc.maxWidth)
+		defer os.RemoveAll(filepath.Dir(filepath.Dir(filepath.Dir(root))))
+		devs, err := Scan(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sds := MatchSystemSlots([]*godmi.SystemSlot{slot}, devs)
+		if len(sds) != 1 || len(sds[0].Devices) != 2 {
+			t.Fatalf("MatchSystemSlots: got %v", sds)
+		}
+		if n := len(sds[0].Degraded()); n != tc.degraded {
+			t.Errorf("Degraded with max %s x%s: got %d, want %d", tc.maxSpeed, tc.maxWidth, n, tc.degraded)
+		}
+	}
+}
+
+func TestMatchOnBoardDevices(t *testing.T) {
+	root := fakeSysfs(t, "8.0 GT/s PCIe", "8")
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(filepath.Dir(root))))
+	devs, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	obd := &godmi.OnBoardDevicesExtendedInformation{
+		ReferenceDesignation: "Onboard LAN",
+		DeviceType:           godmi.OnBoardDevicesExtendedInformationTypeEthernet,
+		BusNumber:            0,
+		DeviceFunctionNumber: 0x1f<<3 | 6,
+	}
+	ods := MatchOnBoardDevices([]*godmi.OnBoardDevicesExtendedInformation{obd}, devs)
+	if len(ods) != 1 || len(ods[0].Devices) != 1 || ods[0].Devices[0].Driver != "e1000e" {
+		t.Errorf("MatchOnBoardDevices: got %v", ods)
+	}
+}
+
+func TestMatchSiblingRootPorts(t *testing.T) {
+	root, err := ioutil.TempDir("", "pci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	bridge := map[string]string{"vendor": "0x8086", "device": "0xa110", "class": "0x060400"}
+	nic := map[string]string{"vendor": "0x8086", "device": "0x1533", "class": "0x020000"}
+	fakeDevice(t, root, "pci0000:00/0000:00:1c.0", bridge, "pcieport")
+	fakeDevice(t, root, "pci0000:00/0000:00:1c.4", bridge, "pcieport")
+	fakeDevice(t, root, "pci0000:00/0000:00:1c.0/0000:01:00.0", nic, "igb")
+	fakeDevice(t, root, "pci0000:00/0000:00:1c.4/0000:02:00.0", nic, "igb")
+	devs, err := Scan(filepath.Join(root, "bus", "pci", "devices"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := &godmi.SystemSlot{
+		Designation:          "SLOT2",
+		Type:                 godmi.SystemSlotTypePCIExpressGen3x4,
+		DeviceFunctionNumber: 0x1c<<3 | 4,
+	}
+	sds := MatchSystemSlots([]*godmi.SystemSlot{slot}, devs)
+	if len(sds[0].Devices) != 1 || sds[0].Devices[0].Address.String() != "0000:02:00.0" {
+		t.Errorf("MatchSystemSlots 00:1c.4: got %v", sds[0].Devices)
+	}
+	obd := &godmi.OnBoardDevicesExtendedInformation{
+		ReferenceDesignation: "LAN1",
+		DeviceType:           godmi.OnBoardDevicesExtendedInformationTypeEthernet,
+		DeviceFunctionNumber: 0x1c << 3,
+	}
+	ods := MatchOnBoardDevices([]*godmi.OnBoardDevicesExtendedInformation{obd}, devs)
+	if len(ods[0].Devices) != 1 || ods[0].Devices[0].Address.String() != "0000:01:00.0" {
+		t.Errorf("MatchOnBoardDevices 00:1c.0: got %v", ods[0].Devices)
+	}
+}
+
+func TestInterfaceNames(t *testing.T) {
+	slots := []*godmi.SystemSlot{{
+		Designation:          "PCIe Slot 2",
+		Type:                 godmi.SystemSlotTypePCIExpressGen3x8,
+		ID:                   2,
+		BusNumber:            0x3b,
+		DeviceFunctionNumber: 0,
+	}}
+	obds := []*godmi.OnBoardDevicesExtendedInformation{{
+		ReferenceDesignation: "NIC1",
+		DeviceType:           godmi.OnBoardDevicesExtendedInformationTypeEthernet,
+		DeviceTypeInstance:   1,
+		BusNumber:            0x19,
+		DeviceFunctionNumber: 0,
+	}}
+	var addrs []Address
+	for _, s := range []string{"0000:19:00.0", "0000:3b:00.0", "0000:3b:00.1", "0000:5e:00.0"} {
+		a, err := ParseAddress(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, a)
+	}
+	want := []InterfaceName{
+		{addrs[0], "eno1", "em1"},
+		{addrs[1], "ens2f0", "p2p1"},
+		{addrs[2], "ens2f1", "p2p2"},
+		{addrs[3], "", ""},
+	}
+	got := InterfaceNames(slots, obds, AddressDevices(addrs))
+	if len(got) != len(want) {
+		t.Fatalf("InterfaceNames: got %v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("InterfaceNames: got %s, want %s", got[i], want[i])
+		}
+	}
+}
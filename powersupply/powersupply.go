@@ -0,0 +1,266 @@
+// Package powersupply reads the batteries of the Linux power supply class
+// and attaches their full charge capacity to the SMBIOS portable batteries
+// (type 22) they belong to, giving the wear of each battery.
+package powersupply
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs"
+)
+
+// DefaultRoot is where the kernel lists power supplies
+const DefaultRoot = "/sys/class/power_supply"
+
+// Battery is a battery of the power supply class. Capacities are in mWh and
+// 0 when the driver does not report them.
+type Battery struct {
+	Name             string
+	Manufacturer     string
+	ModelName        string
+	SerialNumber     string
+	Technology       string
+	Status           string
+	CycleCount       uint64
+	EnergyFull       uint64
+	EnergyFullDesign uint64
+}
+
+func (b Battery) String() string {
+	return fmt.Sprintf("%s %s %s full=%dmWh design=%dmWh cycles=%d",
+		b.Name, b.Manufacturer, b.ModelName, b.EnergyFull, b.EnergyFullDesign, b.CycleCount)
+}
+
+// energy returns an energy attribute in mWh. Drivers that count charge
+// instead report charge_* in uAh, which is converted with the design
+// voltage.
+func energy(dir, name string) uint64 {
+	if e := sysfs.ReadUint(dir, "energy_"+name); e != 0 {
+		return e / 1000
+	}
+	c := sysfs.ReadUint(dir, "charge_"+name)
+	v := sysfs.ReadUint(dir, "voltage_min_design")
+	return c * v / 1000000000
+}
+
+// Scan reads every BAT* battery under root, which is normally DefaultRoot.
+// Batteries are returned in index order.
+func Scan(root string) ([]Battery, error) {
+	fis, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, fi := range fis {
+		if strings.HasPrefix(fi.Name(), "BAT") {
+			names = append(names, fi.Name())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	var bs []Battery
+	for _, n := range names {
+		dir := filepath.Join(root, n)
+		if t := sysfs.ReadString(dir, "type"); t != "" && t != "Battery" {
+			continue
+		}
+		bs = append(bs, Battery{
+			Name:             n,
+			Manufacturer:     sysfs.ReadString(dir, "manufacturer"),
+			ModelName:        sysfs.ReadString(dir, "model_name"),
+			SerialNumber:     sysfs.ReadString(dir, "serial_number"),
+			Technology:       sysfs.ReadString(dir, "technology"),
+			Status:           sysfs.ReadString(dir, "status"),
+			CycleCount:       sysfs.ReadUint(dir, "cycle_count"),
+			EnergyFull:       energy(dir, "full"),
+			EnergyFullDesign: energy(dir, "full_design"),
+		})
+	}
+	return bs, nil
+}
+
+// MatchMethod tells how a battery was tied to a portable battery
+type MatchMethod byte
+
+const (
+	MatchNone MatchMethod = iota
+	MatchSerial
+	MatchModel
+	MatchPosition
+)
+
+func (m MatchMethod) String() string {
+	methods := [...]string{
+		"None",
+		"Serial",
+		"Model",
+		"Position",
+	}
+	if int(m) < len(methods) {
+		return methods[m]
+	}
+	return godmi.OUT_OF_SPEC
+}
+
+// BatteryHealth is the health of a physical battery. PortableBattery is nil
+// for a battery the SMBIOS table does not list, and Battery is nil for a
+// portable battery the kernel does not report.
+type BatteryHealth struct {
+	PortableBattery *godmi.PortableBattery
+	Battery         *Battery
+	Method          MatchMethod
+}
+
+// DesignCapacity returns the design capacity in mWh, from the SMBIOS table
+// when it is known and from the kernel otherwise
+func (h BatteryHealth) DesignCapacity() uint64 {
+	if h.PortableBattery != nil {
+		if c := h.PortableBattery.DesignCapacityMWh(); c != 0 {
+			return uint64(c)
+		}
+	}
+	if h.Battery != nil {
+		return h.Battery.EnergyFullDesign
+	}
+	return 0
+}
+
+// FullChargeCapacity returns the capacity of the battery when fully charged
+// in mWh
+func (h BatteryHealth) FullChargeCapacity() uint64 {
+	if h.Battery == nil {
+		return 0
+	}
+	return h.Battery.EnergyFull
+}
+
+// Wear returns the capacity lost since the battery was made, in percent of
+// the design capacity. It returns false when either capacity is unknown.
+func (h BatteryHealth) Wear() (float64, bool) {
+	d := h.DesignCapacity()
+	f := h.FullChargeCapacity()
+	if d == 0 || f == 0 {
+		return 0, false
+	}
+	w := 100 * (1 - float64(f)/float64(d))
+	if w < 0 {
+		w = 0
+	}
+	return w, true
+}
+
+func (h BatteryHealth) String() string {
+	name := "unknown"
+	if h.PortableBattery != nil {
+		name = strings.TrimSpace(h.PortableBattery.Location + " " + h.PortableBattery.DeviceName)
+	}
+	if h.Battery == nil {
+		return fmt.Sprintf("%s: not reported by the kernel", name)
+	}
+	w, ok := h.Wear()
+	if !ok {
+		return fmt.Sprintf("%s: wear unknown (%s, by %s)", name, h.Battery.Name, h.Method)
+	}
+	return fmt.Sprintf("%s: %.1f%% wear, %d of %d mWh (%s, by %s)",
+		name, w, h.FullChargeCapacity(), h.DesignCapacity(), h.Battery.Name, h.Method)
+}
+
+func same(a, b string) bool {
+	a = strings.TrimSpace(a)
+	return a != "" && strings.EqualFold(a, strings.TrimSpace(b))
+}
+
+// Correlate ties the kernel batteries to the portable batteries.
+//
+// A battery is matched by its serial number, then by its model name against
+// the device name, as long as exactly one portable battery agrees. When as
+// many batteries as portable batteries are left, the rest are paired in
+// order.
+func Correlate(pbs []*godmi.PortableBattery, bs []Battery) []BatteryHealth {
+	matched := make(map[*godmi.PortableBattery]BatteryHealth)
+	used := make(map[*Battery]bool)
+	match := func(method MatchMethod, eq func(p *godmi.PortableBattery, b *Battery) bool) {
+		for i := range bs {
+			b := &bs[i]
+			if used[b] {
+				continue
+			}
+			var cand *godmi.PortableBattery
+			n := 0
+			for _, p := range pbs {
+				if _, ok := matched[p]; ok {
+					continue
+				}
+				if eq(p, b) {
+					cand = p
+					n++
+				}
+			}
+			if n == 1 {
+				matched[cand] = BatteryHealth{PortableBattery: cand, Battery: b, Method: method}
+				used[b] = true
+			}
+		}
+	}
+	match(MatchSerial, func(p *godmi.PortableBattery, b *Battery) bool {
+		return same(b.SerialNumber, p.SerialNumber) ||
+			(p.SBDSSerialNumber != 0 && same(b.SerialNumber, strconv.Itoa(int(p.SBDSSerialNumber))))
+	})
+	match(MatchModel, func(p *godmi.PortableBattery, b *Battery) bool {
+		return same(b.ModelName, p.DeviceName)
+	})
+
+	var restPBs []*godmi.PortableBattery
+	for _, p := range pbs {
+		if _, ok := matched[p]; !ok {
+			restPBs = append(restPBs, p)
+		}
+	}
+	var restBs []*Battery
+	for i := range bs {
+		if !used[&bs[i]] {
+			restBs = append(restBs, &bs[i])
+		}
+	}
+	if len(restPBs) == len(restBs) {
+		for i, p := range restPBs {
+			matched[p] = BatteryHealth{PortableBattery: p, Battery: restBs[i], Method: MatchPosition}
+			used[restBs[i]] = true
+		}
+	}
+
+	var hs []BatteryHealth
+	for _, p := range pbs {
+		if h, ok := matched[p]; ok {
+			hs = append(hs, h)
+		} else {
+			hs = append(hs, BatteryHealth{PortableBattery: p})
+		}
+	}
+	for i := range bs {
+		if !used[&bs[i]] {
+			hs = append(hs, BatteryHealth{Battery: &bs[i]})
+		}
+	}
+	return hs
+}
+
+// GetBatteryHealth scans root and attaches its batteries to the portable
+// batteries of the SMBIOS table
+func GetBatteryHealth(root string) ([]BatteryHealth, error) {
+	bs, err := Scan(root)
+	if err != nil {
+		return nil, err
+	}
+	return Correlate(godmi.GetPortableBatteries(), bs), nil
+}
@@ -0,0 +1,151 @@
+package powersupply
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ochapman/godmi"
+	"github.com/ochapman/godmi/internal/sysfs/sysfstest"
+)
+
+func TestScan(t *testing.T) {
+	root, err := ioutil.TempDir("", "powersupply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := sysfstest.WriteTree(root, map[string]string{
+		"AC/type":   "Mains",
+		"AC/online": "1",
+		// A peripheral battery is not a system battery
+		"BAT3/type":         "USB",
+		"BAT10/type":        "Battery",
+		"BAT10/energy_full": "50000000",
+		"BAT2/type":         "Battery",
+		"BAT2/cycle_count":  "312",
+		// Charge based drivers report uAh
+		"BAT2/charge_full":        "3000000",
+		"BAT2/charge_full_design": "4000000",
+		"BAT2/voltage_min_design": "11400000",
+		// Old drivers have no type attribute
+		"BAT1/model_name": "5B10W13930",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	bs, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name             string
+		full, fullDesign uint64
+	}{
+		{"BAT1", 0, 0},
+		{"BAT2", 34200, 45600},
+		{"BAT10", 50000, 0},
+	}
+	if len(bs) != len(want) {
+		t.Fatalf("Scan: got %v", bs)
+	}
+	for i, w := range want {
+		if b := bs[i]; b.Name != w.name || b.EnergyFull != w.full || b.EnergyFullDesign != w.fullDesign {
+			t.Errorf("Scan %d: got %+v", i, b)
+		}
+	}
+	if bs[0].ModelName != "5B10W13930" || bs[1].CycleCount != 312 {
+		t.Errorf("Scan: got %v", bs)
+	}
+	if _, err := Scan(filepath.Join(root, "missing")); err == nil {
+		t.Error("Scan: expected error for missing root")
+	}
+}
+
+func TestCorrelate(t *testing.T) {
+	bs := []Battery{
+		{Name: "BAT0", ModelName: "LNV-45N1", SerialNumber: "1234", EnergyFull: 45120, EnergyFullDesign: 57000},
+		{Name: "BAT1", ModelName: "DELL 3RNFD", SerialNumber: " ab12 ", EnergyFull: 34200},
+	}
+	pbs := []*godmi.PortableBattery{
+		// The device name agrees with BAT0, but the serial wins
+		{DeviceName: "LNV-45N1", SerialNumber: "AB12", DesignCapacity: 4800, DesignCapacityMultiplier: 10},
+		{DeviceName: "LNV-45N1", SBDSSerialNumber: 1234},
+	}
+	hs := Correlate(pbs, bs)
+	want := []struct {
+		name   string
+		method MatchMethod
+		wear   float64
+	}{
+		{"BAT1", MatchSerial, 28.75},
+		// The design capacity comes from the kernel when the table has none
+		{"BAT0", MatchSerial, 20.84},
+	}
+	if len(hs) != len(want) {
+		t.Fatalf("Correlate: got %v", hs)
+	}
+	for i, w := range want {
+		h := hs[i]
+		wear, ok := h.Wear()
+		if h.Battery == nil || h.Battery.Name != w.name || h.Method != w.method ||
+			!ok || wear < w.wear-0.01 || wear > w.wear+0.01 {
+			t.Errorf("Correlate %d: got %v, want %v", i, h, w)
+		}
+	}
+}
+
+func TestCorrelateAmbiguousModel(t *testing.T) {
+	// Two portable batteries share the model name, so the model decides
+	// nothing and the batteries are paired in order
+	bs := []Battery{{Name: "BAT0", ModelName: "45N1"}, {Name: "BAT1", ModelName: "45N1"}}
+	pbs := []*godmi.PortableBattery{{DeviceName: "45N1"}, {DeviceName: "45n1"}}
+	hs := Correlate(pbs, bs)
+	if len(hs) != 2 {
+		t.Fatalf("Correlate: got %v", hs)
+	}
+	for i, h := range hs {
+		if h.Battery != &bs[i] || h.Method != MatchPosition {
+			t.Errorf("Correlate %d: got %v", i, h)
+		}
+	}
+}
+
+func TestCorrelateCountMismatch(t *testing.T) {
+	// Without a match and with a battery too many, nothing is paired
+	bs := []Battery{{Name: "BAT0"}, {Name: "BAT1"}}
+	pbs := []*godmi.PortableBattery{{DeviceName: "Primary", Location: "Front"}}
+	hs := Correlate(pbs, bs)
+	if len(hs) != 3 {
+		t.Fatalf("Correlate: got %v", hs)
+	}
+	if hs[0].Battery != nil || hs[0].String() != "Front Primary: not reported by the kernel" {
+		t.Errorf("Correlate: got %v", hs[0])
+	}
+	if hs[1].PortableBattery != nil || hs[1].Battery != &bs[0] || hs[2].Battery != &bs[1] {
+		t.Errorf("Correlate: got %v", hs[1:])
+	}
+}
+
+func TestWear(t *testing.T) {
+	for _, c := range []struct {
+		b    Battery
+		wear float64
+		ok   bool
+	}{
+		{Battery{EnergyFull: 37500, EnergyFullDesign: 50000}, 25, true},
+		// A recalibrated battery can report more than its design
+		{Battery{EnergyFull: 52000, EnergyFullDesign: 50000}, 0, true},
+		{Battery{EnergyFull: 40000}, 0, false},
+		{Battery{EnergyFullDesign: 50000}, 0, false},
+	} {
+		b := c.b
+		w, ok := BatteryHealth{Battery: &b}.Wear()
+		if w != c.wear || ok != c.ok {
+			t.Errorf("Wear(%v) = %v, %v, want %v, %v", c.b, w, ok, c.wear, c.ok)
+		}
+	}
+	if _, ok := (BatteryHealth{PortableBattery: &godmi.PortableBattery{}}).Wear(); ok {
+		t.Error("Wear: expected unknown without a battery")
+	}
+}
@@ -0,0 +1,209 @@
+package godmi
+
+import (
+	"fmt"
+)
+
+type SecurityCheckResult byte
+
+const (
+	SecurityCheckPass SecurityCheckResult = iota
+	SecurityCheckWarn
+	SecurityCheckFail
+)
+
+func (s SecurityCheckResult) String() string {
+	results := [...]string{
+		"pass",
+		"warn",
+		"fail",
+	}
+	if int(s) < len(results) {
+		return results[s]
+	}
+	return OUT_OF_SPEC
+}
+
+// MarshalText lets the result be encoded by name
+func (s SecurityCheckResult) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// SecurityCheck is one item of a security posture. ID is stable across
+// releases so that scanners can key on it.
+type SecurityCheck struct {
+	ID     string                `json:"id"`
+	Type   SMBIOSStructureType   `json:"type"`
+	Handle SMBIOSStructureHandle `json:"handle"`
+	Result SecurityCheckResult   `json:"result"`
+	Detail string                `json:"detail"`
+}
+
+func (s SecurityCheck) String() string {
+	return fmt.Sprintf("[%s] %s (handle 0x%04X): %s", s.Result, s.ID, uint16(s.Handle), s.Detail)
+}
+
+// SecurityPosture is the result of AssessSecurityPosture
+type SecurityPosture []SecurityCheck
+
+// Result returns the worst result of the checks
+func (p SecurityPosture) Result() SecurityCheckResult {
+	r := SecurityCheckPass
+	for _, c := range p {
+		if c.Result > r {
+			r = c.Result
+		}
+	}
+	return r
+}
+
+func (p SecurityPosture) String() string {
+	s := fmt.Sprintf("Security Posture: %s", p.Result())
+	for _, c := range p {
+		s += "\n\t" + c.String()
+	}
+	return s
+}
+
+func passIf(ok bool, otherwise SecurityCheckResult) SecurityCheckResult {
+	if ok {
+		return SecurityCheckPass
+	}
+	return otherwise
+}
+
+// passwordResult grades a password status. A password the firmware does not
+// implement or report cannot be relied on, so it is at best a warning.
+func passwordResult(s HardwareSecurityStatus, disabled SecurityCheckResult) SecurityCheckResult {
+	switch s {
+	case HardwareSecurityStatusEnabled:
+		return SecurityCheckPass
+	case HardwareSecurityStatusDisabled:
+		return disabled
+	}
+	return SecurityCheckWarn
+}
+
+func hardwareSecurityChecks(h *HardwareSecurity) []SecurityCheck {
+	s := h.Setting
+	check := func(id string, r SecurityCheckResult, name string, st HardwareSecurityStatus) SecurityCheck {
+		return SecurityCheck{
+			ID:     id,
+			Type:   SMBIOSStructureTypeHardwareSecurity,
+			Handle: h.Handle,
+			Result: r,
+			Detail: fmt.Sprintf("%s is %s", name, st),
+		}
+	}
+	frontPanel := SecurityCheckWarn
+	switch s.FrontPanelReset {
+	case HardwareSecurityStatusDisabled, HardwareSecurityStatusNotImplemented:
+		frontPanel = SecurityCheckPass
+	}
+	return []SecurityCheck{
+		check("hardware-security.power-on-password",
+			passwordResult(s.PowerOnPassword, SecurityCheckWarn), "Power-on password", s.PowerOnPassword),
+		check("hardware-security.keyboard-password",
+			passwordResult(s.KeyboardPassword, SecurityCheckWarn), "Keyboard password", s.KeyboardPassword),
+		check("hardware-security.administrator-password",
+			passwordResult(s.AdministratorPassword, SecurityCheckFail), "Administrator password", s.AdministratorPassword),
+		check("hardware-security.front-panel-reset",
+			frontPanel, "Front panel reset", s.FrontPanelReset),
+	}
+}
+
+func outOfBandRemoteAccessChecks(o *OutOfBandRemoteAccess) []SecurityCheck {
+	c := o.Connections
+	return []SecurityCheck{
+		{
+			ID:     "remote-access.inbound",
+			Type:   SMBIOSStructureTypeOut_of_bandRemoteAccess,
+			Handle: o.Handle,
+			Result: passIf(!c.InBoundEnabled, SecurityCheckWarn),
+			Detail: fmt.Sprintf("Inbound connections through %s are %s",
+				o.ManufacturerName, enabled(c.InBoundEnabled)),
+		},
+		{
+			ID:     "remote-access.outbound",
+			Type:   SMBIOSStructureTypeOut_of_bandRemoteAccess,
+			Handle: o.Handle,
+			Result: passIf(!c.OutBoundEnabled, SecurityCheckWarn),
+			Detail: fmt.Sprintf("Outbound connections through %s are %s",
+				o.ManufacturerName, enabled(c.OutBoundEnabled)),
+		},
+	}
+}
+
+func chassisChecks(c *ChassisInformation) []SecurityCheck {
+	status := SecurityCheckWarn
+	switch c.SecurityStatus {
+	case ChassisSecurityStatusExternalInterfaceLockedOut:
+		status = SecurityCheckPass
+	case ChassisSecurityStatusNone:
+		status = SecurityCheckFail
+	}
+	return []SecurityCheck{
+		{
+			ID:     "chassis.security-status",
+			Type:   SMBIOSStructureTypeChassis,
+			Handle: c.Handle,
+			Result: status,
+			Detail: fmt.Sprintf("Chassis security status is %s", c.SecurityStatus),
+		},
+		{
+			ID:     "chassis.lock",
+			Type:   SMBIOSStructureTypeChassis,
+			Handle: c.Handle,
+			Result: passIf(c.Lock != 0, SecurityCheckWarn),
+			Detail: fmt.Sprintf("Chassis lock is %s", c.Lock),
+		},
+	}
+}
+
+func biosChecks(b *BIOSInformation) []SecurityCheck {
+	check := func(id string, set bool, what string) SecurityCheck {
+		return SecurityCheck{
+			ID:     id,
+			Type:   SMBIOSStructureTypeBIOS,
+			Handle: b.Handle,
+			Result: passIf(!set, SecurityCheckWarn),
+			Detail: fmt.Sprintf("%s: %s", what, yesNo(set)),
+		}
+	}
+	return []SecurityCheck{
+		check("bios.upgradeable",
+			b.Characteristics&BIOSCharacteristicsUpgradeable != 0,
+			"BIOS upgradeable"),
+		check("bios.boot-from-cd",
+			b.Characteristics&BIOSCharacteristicsBootFromCDSupported != 0,
+			"Boot from CD allowed"),
+		check("bios.network-boot",
+			b.CharacteristicsExt2&BIOSCharacteristicsExt2FuncKeyInitiatedNetworkBootSupported != 0,
+			"Network boot allowed"),
+	}
+}
+
+// AssessSecurityPosture grades the security relevant settings the firmware
+// reports. Structures that are nil or missing contribute no checks, so a
+// table without type 24 yields no password checks rather than failures.
+func AssessSecurityPosture(b *BIOSInformation, c *ChassisInformation, hs []*HardwareSecurity, rs []*OutOfBandRemoteAccess) SecurityPosture {
+	var p SecurityPosture
+	for _, h := range hs {
+		p = append(p, hardwareSecurityChecks(h)...)
+	}
+	for _, o := range rs {
+		p = append(p, outOfBandRemoteAccessChecks(o)...)
+	}
+	if c != nil {
+		p = append(p, chassisChecks(c)...)
+	}
+	if b != nil {
+		p = append(p, biosChecks(b)...)
+	}
+	return p
+}
+
+func GetSecurityPosture() SecurityPosture {
+	return AssessSecurityPosture(GetBIOSInformation(), GetChassisInformation(),
+		GetHardwareSecurities(), GetOutOfBandRemoteAccesses())
+}
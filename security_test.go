@@ -0,0 +1,92 @@
+package godmi
+
+import "testing"
+
+func TestAssessSecurityPosture(t *testing.T) {
+	// Power-on enabled, keyboard disabled, administrator not implemented,
+	// front panel reset unknown
+	hs := &HardwareSecurity{infoCommon: infoCommon{Handle: 0x24}, Setting: NewHardwareSecurity(0x4B)}
+	rs := &OutOfBandRemoteAccess{
+		infoCommon:       infoCommon{Handle: 0x30},
+		ManufacturerName: "BMC",
+		Connections:      NewOutOfBandRemoteAccessConnections(0x01),
+	}
+	c := &ChassisInformation{
+		infoCommon:     infoCommon{Handle: 0x03},
+		SecurityStatus: ChassisSecurityStatusNone,
+		Lock:           1,
+	}
+	b := &BIOSInformation{
+		infoCommon:      infoCommon{Handle: 0x00},
+		Characteristics: BIOSCharacteristicsUpgradeable,
+	}
+	p := AssessSecurityPosture(b, c, []*HardwareSecurity{hs}, []*OutOfBandRemoteAccess{rs})
+	want := []struct {
+		id     string
+		handle SMBIOSStructureHandle
+		result SecurityCheckResult
+	}{
+		{"hardware-security.power-on-password", 0x24, SecurityCheckPass},
+		{"hardware-security.keyboard-password", 0x24, SecurityCheckWarn},
+		{"hardware-security.administrator-password", 0x24, SecurityCheckWarn},
+		{"hardware-security.front-panel-reset", 0x24, SecurityCheckWarn},
+		{"remote-access.inbound", 0x30, SecurityCheckWarn},
+		{"remote-access.outbound", 0x30, SecurityCheckPass},
+		{"chassis.security-status", 0x03, SecurityCheckFail},
+		{"chassis.lock", 0x03, SecurityCheckPass},
+		{"bios.upgradeable", 0x00, SecurityCheckWarn},
+		{"bios.boot-from-cd", 0x00, SecurityCheckPass},
+		{"bios.network-boot", 0x00, SecurityCheckPass},
+	}
+	if len(p) != len(want) {
+		t.Fatalf("AssessSecurityPosture: got %d checks, want %d\n%s", len(p), len(want), p)
+	}
+	for i, w := range want {
+		if p[i].ID != w.id || p[i].Handle != w.handle || p[i].Result != w.result {
+			t.Errorf("check %d: got %s, want %s %s", i, p[i], w.id, w.result)
+		}
+	}
+	if p.Result() != SecurityCheckFail {
+		t.Errorf("Result: got %s, want fail", p.Result())
+	}
+}
+
+func TestAssessSecurityPostureAdministratorPassword(t *testing.T) {
+	for _, tc := range []struct {
+		status HardwareSecurityStatus
+		result SecurityCheckResult
+	}{
+		{HardwareSecurityStatusEnabled, SecurityCheckPass},
+		{HardwareSecurityStatusDisabled, SecurityCheckFail},
+		{HardwareSecurityStatusNotImplemented, SecurityCheckWarn},
+		{HardwareSecurityStatusUnknown, SecurityCheckWarn},
+	} {
+		hs := &HardwareSecurity{Setting: HardwareSecuritySettings{AdministratorPassword: tc.status}}
+		for _, c := range AssessSecurityPosture(nil, nil, []*HardwareSecurity{hs}, nil) {
+			if c.ID == "hardware-security.administrator-password" && c.Result != tc.result {
+				t.Errorf("administrator password %s: got %s, want %s", tc.status, c.Result, tc.result)
+			}
+		}
+	}
+}
+
+func TestAssessSecurityPostureMissing(t *testing.T) {
+	p := AssessSecurityPosture(nil, nil, nil, nil)
+	if len(p) != 0 || p.Result() != SecurityCheckPass {
+		t.Errorf("AssessSecurityPosture without structures: got %s", p)
+	}
+}
+
+func TestHardwareSecurityDecode(t *testing.T) {
+	loadTable(structure(24, 0x24, []byte{0x4B}))
+	h := GetHardwareSecurity()
+	want := HardwareSecuritySettings{
+		PowerOnPassword:       HardwareSecurityStatusEnabled,
+		KeyboardPassword:      HardwareSecurityStatusDisabled,
+		AdministratorPassword: HardwareSecurityStatusNotImplemented,
+		FrontPanelReset:       HardwareSecurityStatusUnknown,
+	}
+	if h == nil || h.Setting != want {
+		t.Errorf("GetHardwareSecurity: got %v", h)
+	}
+}
@@ -0,0 +1,34 @@
+package godmi
+
+// structure builds the raw bytes of a structure: its header, the formatted
+// area body and the string set. An empty string would end the set early,
+// so strs must not contain one.
+func structure(typ byte, handle uint16, body []byte, strs ...string) []byte {
+	b := []byte{typ, byte(4 + len(body)), byte(handle), byte(handle >> 8)}
+	b = append(b, body...)
+	if len(strs) == 0 {
+		return append(b, 0, 0)
+	}
+	for _, s := range strs {
+		b = append(b, s...)
+		b = append(b, 0)
+	}
+	return append(b, 0)
+}
+
+// loadTable decodes the structures as if they were the SMBIOS table, so the
+// getters return them
+func loadTable(ss ...[]byte) {
+	var t []byte
+	for _, s := range ss {
+		t = append(t, s...)
+	}
+	t = append(t, structure(byte(SMBIOSStructureTypeEndOfTable), 0xFEFF, nil)...)
+	indexStructures(decodeStructures(t))
+}
+
+// put16 stores v little endian at b[off:]
+func put16(b []byte, off int, v uint16) {
+	b[off] = byte(v)
+	b[off+1] = byte(v >> 8)
+}
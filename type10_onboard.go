@@ -38,12 +38,15 @@ func (t OnBoardDeviceTypeOfDevice) String() string {
 		"SATA Controller",
 		"SAS Controller",
 	}
-	return types[t-1]
+	if t >= OnBoardDeviceOther && t <= OnBoardDeviceSASController {
+		return types[t-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type OnBoardDeviceType struct {
-	status       bool
-	typeOfDevice OnBoardDeviceTypeOfDevice
+	Enabled    bool
+	DeviceType OnBoardDeviceTypeOfDevice
 }
 
 type OnBoardDeviceInformation struct {
@@ -54,12 +57,35 @@ type OnBoardDeviceInformation struct {
 
 func (d OnBoardDeviceInformation) String() string {
 	var info string
-	title := "On Board Devices Information"
 	for i, v := range d.Type {
-		s := fmt.Sprintf("Device %d: Enabled: %v: Description: %s", i, v.status, v.typeOfDevice, d.Description[i])
-		info += "\n\t\t" + s
+		if i > 0 {
+			info += "\n"
+		}
+		info += fmt.Sprintf("On Board Device %d Information\n"+
+			"\tType: %s\n"+
+			"\tStatus: %s\n"+
+			"\tDescription: %s",
+			i+1,
+			v.DeviceType,
+			enabled(v.Enabled),
+			d.Description[i])
 	}
-	return title + "\n\t\t" + info
+	return info
+}
+
+// ExtendedInformation returns the On Board Devices Extended Information
+// (type 41) whose reference designation matches the description of
+// device i, or nil
+func (d OnBoardDeviceInformation) ExtendedInformation(i int) *OnBoardDevicesExtendedInformation {
+	if i < 0 || i >= len(d.Description) {
+		return nil
+	}
+	for _, o := range GetOnBoardDevicesExtendedInformations() {
+		if o.ReferenceDesignation == d.Description[i] {
+			return o
+		}
+	}
+	return nil
 }
 
 func newOnBoardDeviceInformation(h dmiHeader) dmiTyper {
@@ -70,8 +96,8 @@ func newOnBoardDeviceInformation(h dmiHeader) dmiTyper {
 		var t OnBoardDeviceType
 		index := 4 + 2*(i-1)
 		sindex := 5 + 2*(i-1)
-		t.status = data[index]&0x80 != 0
-		t.typeOfDevice = OnBoardDeviceTypeOfDevice(data[index] & 0x7F)
+		t.Enabled = data[index]&0x80 != 0
+		t.DeviceType = OnBoardDeviceTypeOfDevice(data[index] & 0x7F)
 		d.Type = append(d.Type, t)
 		desc := h.FieldString(int(data[sindex]))
 		d.Description = append(d.Description, desc)
@@ -86,6 +112,14 @@ func GetOnBoardDeviceInformation() *OnBoardDeviceInformation {
 	return nil
 }
 
+func GetOnBoardDeviceInformations() []*OnBoardDeviceInformation {
+	var ds []*OnBoardDeviceInformation
+	for _, d := range GetStructures(SMBIOSStructureTypeOnBoardDevices) {
+		ds = append(ds, d.(*OnBoardDeviceInformation))
+	}
+	return ds
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeOnBoardDevices, newOnBoardDeviceInformation)
 }
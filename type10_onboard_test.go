@@ -0,0 +1,67 @@
+package godmi
+
+import "testing"
+
+func onBoardDeviceExtended(handle uint16, typ byte, segment uint16, bus, devfn byte, designation string) []byte {
+	return structure(41, handle, []byte{1, typ, 1, byte(segment), byte(segment >> 8), bus, devfn}, designation)
+}
+
+func TestNewOnBoardDeviceInformation(t *testing.T) {
+	loadTable(
+		structure(10, 0x1000, []byte{0x80 | byte(OnBoardDeviceVideo), 1, byte(OnBoardDeviceEthernet), 2}, "VGA", "LAN1"),
+		// Every type 10 structure is decoded, not only the first
+		structure(10, 0x1001, []byte{0x80 | byte(OnBoardDeviceSATAController), 1}, "SATA"),
+		onBoardDeviceExtended(0x4100, 0x80|byte(OnBoardDevicesExtendedInformationTypeEthernet), 0, 0x02, 0x08, "LAN1"),
+		// A device off the PCI bus has no address
+		onBoardDeviceExtended(0x4101, byte(OnBoardDevicesExtendedInformationTypeSound), 0xFFFF, 0xFF, 0xFF, "Audio"))
+
+	ds := GetOnBoardDeviceInformations()
+	if len(ds) != 2 || len(ds[0].Type) != 2 || len(ds[1].Type) != 1 {
+		t.Fatalf("GetOnBoardDeviceInformations: got %v", ds)
+	}
+	want := []struct {
+		typ     OnBoardDeviceType
+		desc    string
+		address string
+	}{
+		{OnBoardDeviceType{true, OnBoardDeviceVideo}, "VGA", ""},
+		{OnBoardDeviceType{false, OnBoardDeviceEthernet}, "LAN1", "0000:02:01.0"},
+	}
+	for i, w := range want {
+		if ds[0].Type[i] != w.typ || ds[0].Description[i] != w.desc {
+			t.Errorf("device %d: got %v %q", i, ds[0].Type[i], ds[0].Description[i])
+		}
+		var address string
+		if e := ds[0].ExtendedInformation(i); e != nil {
+			address = e.BusAddress()
+		}
+		if address != w.address {
+			t.Errorf("device %d: got bus address %q, want %q", i, address, w.address)
+		}
+	}
+	if ds[1].Type[0] != (OnBoardDeviceType{true, OnBoardDeviceSATAController}) || ds[1].Description[0] != "SATA" {
+		t.Errorf("second structure: got %v", ds[1])
+	}
+	if ds[0].ExtendedInformation(2) != nil {
+		t.Error("ExtendedInformation past the last device: expected nil")
+	}
+}
+
+func TestNewOnBoardDevicesExtendedInformation(t *testing.T) {
+	loadTable(
+		onBoardDeviceExtended(0x4100, 0x80|byte(OnBoardDevicesExtendedInformationTypeEthernet), 0, 0x02, 0x0A, "LAN1"),
+		onBoardDeviceExtended(0x4101, byte(OnBoardDevicesExtendedInformationTypeSound), 0xFFFF, 0xFF, 0xFF, "Audio"))
+	os := GetOnBoardDevicesExtendedInformations()
+	if len(os) != 2 {
+		t.Fatalf("GetOnBoardDevicesExtendedInformations: got %v", os)
+	}
+	lan, audio := os[0], os[1]
+	if !lan.Enabled || lan.DeviceType != OnBoardDevicesExtendedInformationTypeEthernet ||
+		lan.BusAddress() != "0000:02:01.2" || lan.SlotSegment() != "Bus Address: 0000:02:01.2" {
+		t.Errorf("LAN1: got %v", lan)
+	}
+	if audio.Enabled || audio.DeviceType != OnBoardDevicesExtendedInformationTypeSound ||
+		audio.BusAddress() != "" || audio.SlotSegment() != "Not of types PCI/AGP/PCI-X/PCI-Express" {
+		t.Errorf("Audio: got %v", audio)
+	}
+}
@@ -8,24 +8,72 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type OEMStrings struct {
 	infoCommon
-	Count   byte
-	strings string
+	Count  byte
+	Values []string
 }
 
 func (o OEMStrings) String() string {
-	return fmt.Sprintf("OEM strings: %s", o.strings)
+	s := "OEM Strings"
+	for i, v := range o.Values {
+		s += fmt.Sprintf("\n\tString %d: %s", i+1, v)
+	}
+	return s
+}
+
+// OEMKeyValue is an OEM string of the form key=value or key:value
+type OEMKeyValue struct {
+	Key   string
+	Value string
+}
+
+// ParseOEMKeyValue splits s at the first '=' or ':'. It reports false if s
+// has neither or the key is empty.
+func ParseOEMKeyValue(s string) (OEMKeyValue, bool) {
+	i := strings.IndexAny(s, "=:")
+	if i <= 0 {
+		return OEMKeyValue{}, false
+	}
+	kv := OEMKeyValue{
+		Key:   strings.TrimSpace(s[:i]),
+		Value: strings.TrimSpace(s[i+1:]),
+	}
+	return kv, kv.Key != ""
+}
+
+// KeyValues returns the strings that follow the key=value or key:value
+// convention, in order
+func (o OEMStrings) KeyValues() []OEMKeyValue {
+	var kvs []OEMKeyValue
+	for _, v := range o.Values {
+		if kv, ok := ParseOEMKeyValue(v); ok {
+			kvs = append(kvs, kv)
+		}
+	}
+	return kvs
+}
+
+// Lookup returns the value of the first key=value or key:value string
+// whose key matches key, ignoring case
+func (o OEMStrings) Lookup(key string) (string, bool) {
+	for _, kv := range o.KeyValues() {
+		if strings.EqualFold(kv.Key, key) {
+			return kv.Value, true
+		}
+	}
+	return "", false
 }
 
 func newOEMStrings(h dmiHeader) dmiTyper {
 	var o OEMStrings
 	data := h.data
 	o.Count = data[0x04]
-	for i := byte(0); i < o.Count; i++ {
-		o.strings += fmt.Sprintf("strings: %d %s\n\t\t", i, h.FieldString(int(data[i])))
+	for i := 1; i <= int(o.Count); i++ {
+		o.Values = append(o.Values, h.FieldString(i))
 	}
 	return &o
 }
@@ -37,6 +85,20 @@ func GetOEMStrings() *OEMStrings {
 	return nil
 }
 
+// GetOEMStringValues returns the strings of every OEM Strings structure
+func GetOEMStringValues() []string {
+	var vs []string
+	for _, d := range GetStructures(SMBIOSStructureTypeOEMStrings) {
+		vs = append(vs, d.(*OEMStrings).Values...)
+	}
+	return vs
+}
+
+// LookupOEMString looks key up in every OEM Strings structure
+func LookupOEMString(key string) (string, bool) {
+	return OEMStrings{Values: GetOEMStringValues()}.Lookup(key)
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeOEMStrings, newOEMStrings)
 }
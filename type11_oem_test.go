@@ -0,0 +1,63 @@
+package godmi
+
+import "testing"
+
+func TestNewOEMStrings(t *testing.T) {
+	loadTable(
+		structure(11, 0x0B00, []byte{4}, "Dell System", "1[0817]", "Platform = X11 ", "io.systemd.credential:vmm.notify_socket=vsock:2:1234"),
+		structure(11, 0x0B01, []byte{1}, "platform:Y12"))
+
+	ss := GetStructures(SMBIOSStructureTypeOEMStrings)
+	if len(ss) != 2 {
+		t.Fatalf("GetStructures: got %d OEM Strings", len(ss))
+	}
+	o := ss[0].(*OEMStrings)
+	// Each value is its own string, the first one included
+	want := []string{"Dell System", "1[0817]", "Platform = X11 ", "io.systemd.credential:vmm.notify_socket=vsock:2:1234"}
+	if int(o.Count) != len(want) || len(o.Values) != len(want) {
+		t.Fatalf("Values: got %q", o.Values)
+	}
+	for i, w := range want {
+		if o.Values[i] != w {
+			t.Errorf("Values[%d]: got %q, want %q", i, o.Values[i], w)
+		}
+	}
+	if kvs := o.KeyValues(); len(kvs) != 2 ||
+		kvs[1] != (OEMKeyValue{"io.systemd.credential", "vmm.notify_socket=vsock:2:1234"}) {
+		t.Errorf("KeyValues: got %v", kvs)
+	}
+	if v, ok := o.Lookup("PLATFORM"); !ok || v != "X11" {
+		t.Errorf("Lookup: got %q, %t", v, ok)
+	}
+	if _, ok := o.Lookup("Dell System"); ok {
+		t.Error("Lookup of a string without a separator: expected false")
+	}
+	// The first structure answers before the second
+	if v, ok := LookupOEMString("platform"); !ok || v != "X11" {
+		t.Errorf("LookupOEMString: got %q, %t", v, ok)
+	}
+	if vs := GetOEMStringValues(); len(vs) != 5 || vs[4] != "platform:Y12" {
+		t.Errorf("GetOEMStringValues: got %q", vs)
+	}
+}
+
+func TestParseOEMKeyValue(t *testing.T) {
+	for _, c := range []struct {
+		s  string
+		kv OEMKeyValue
+		ok bool
+	}{
+		{"key=value", OEMKeyValue{"key", "value"}, true},
+		{" key : value ", OEMKeyValue{"key", "value"}, true},
+		{"url=http://host:80", OEMKeyValue{"url", "http://host:80"}, true},
+		{"key=", OEMKeyValue{"key", ""}, true},
+		{"=value", OEMKeyValue{}, false},
+		{" =value", OEMKeyValue{"", "value"}, false},
+		{"no separator", OEMKeyValue{}, false},
+	} {
+		kv, ok := ParseOEMKeyValue(c.s)
+		if kv != c.kv || ok != c.ok {
+			t.Errorf("ParseOEMKeyValue(%q) = %v, %t, want %v, %t", c.s, kv, ok, c.kv, c.ok)
+		}
+	}
+}
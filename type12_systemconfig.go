@@ -8,24 +8,64 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type SystemConfigurationOptions struct {
 	infoCommon
 	Count   byte
-	strings string
+	Options []string
 }
 
 func (s SystemConfigurationOptions) String() string {
-	return fmt.Sprintf("System Configuration Option\n\t\t%s", s.strings)
+	str := "System Configuration Options"
+	for i, o := range s.Options {
+		str += fmt.Sprintf("\n\tOption %d: %s", i+1, o)
+	}
+	return str
+}
+
+// SystemConfigurationSetting is an option of the form name: value, which is
+// how jumper and switch settings are usually described
+type SystemConfigurationSetting struct {
+	Name  string
+	Value string
+}
+
+// ParseSystemConfigurationSetting splits s at the first ':', or the first
+// '=' if there is none. It reports false if s has neither.
+func ParseSystemConfigurationSetting(s string) (SystemConfigurationSetting, bool) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		i = strings.IndexByte(s, '=')
+	}
+	if i <= 0 {
+		return SystemConfigurationSetting{}, false
+	}
+	sc := SystemConfigurationSetting{
+		Name:  strings.TrimSpace(s[:i]),
+		Value: strings.TrimSpace(s[i+1:]),
+	}
+	return sc, sc.Name != ""
+}
+
+// Settings returns the options that parse as name: value, in order
+func (s SystemConfigurationOptions) Settings() []SystemConfigurationSetting {
+	var scs []SystemConfigurationSetting
+	for _, o := range s.Options {
+		if sc, ok := ParseSystemConfigurationSetting(o); ok {
+			scs = append(scs, sc)
+		}
+	}
+	return scs
 }
 
 func newSystemConfigurationOptions(h dmiHeader) dmiTyper {
 	var sc SystemConfigurationOptions
 	data := h.data
 	sc.Count = data[0x04]
-	for i := byte(1); i <= sc.Count; i++ {
-		sc.strings += fmt.Sprintf("string %d: %s\n\t\t", i, h.FieldString(int(data[0x04+i])))
+	for i := 1; i <= int(sc.Count); i++ {
+		sc.Options = append(sc.Options, h.FieldString(i))
 	}
 	return &sc
 }
@@ -0,0 +1,36 @@
+package godmi
+
+import "testing"
+
+func TestNewSystemConfigurationOptions(t *testing.T) {
+	loadTable(structure(12, 0x0C00, []byte{3}, "JP1: 1-2 Clear CMOS", "SW2=ON", "Jumper settings"))
+	sc := GetSystemConfigurationOptions()
+	if sc == nil || sc.Count != 3 || len(sc.Options) != 3 || sc.Options[0] != "JP1: 1-2 Clear CMOS" ||
+		sc.Options[1] != "SW2=ON" || sc.Options[2] != "Jumper settings" {
+		t.Fatalf("GetSystemConfigurationOptions: got %v", sc)
+	}
+	scs := sc.Settings()
+	if len(scs) != 2 || scs[0] != (SystemConfigurationSetting{"JP1", "1-2 Clear CMOS"}) ||
+		scs[1] != (SystemConfigurationSetting{"SW2", "ON"}) {
+		t.Errorf("Settings: got %v", scs)
+	}
+}
+
+func TestParseSystemConfigurationSetting(t *testing.T) {
+	for _, c := range []struct {
+		s  string
+		sc SystemConfigurationSetting
+		ok bool
+	}{
+		// The colon wins over an earlier equals sign
+		{"A=B: C", SystemConfigurationSetting{"A=B", "C"}, true},
+		{"SW1 = off", SystemConfigurationSetting{"SW1", "off"}, true},
+		{": value", SystemConfigurationSetting{}, false},
+		{"plain text", SystemConfigurationSetting{}, false},
+	} {
+		sc, ok := ParseSystemConfigurationSetting(c.s)
+		if sc != c.sc || ok != c.ok {
+			t.Errorf("ParseSystemConfigurationSetting(%q) = %v, %t, want %v, %t", c.s, sc, ok, c.sc, c.ok)
+		}
+	}
+}
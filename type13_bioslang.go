@@ -8,6 +8,7 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type BIOSLanguageInformationFlag byte
@@ -18,7 +19,58 @@ const (
 )
 
 func NewBIOSLanguageInformationFlag(f byte) BIOSLanguageInformationFlag {
-	return BIOSLanguageInformationFlag(f & 0xFE)
+	return BIOSLanguageInformationFlag(f & 0x01)
+}
+
+func (f BIOSLanguageInformationFlag) String() string {
+	if f == BIOSLanguageInformationFlagAbbreviatedFormat {
+		return "Abbreviated"
+	}
+	return "Long"
+}
+
+// BIOSLanguage is a language string split into its ISO 639-1 language code,
+// ISO 3166-1 territory code and, in the long format, encoding
+type BIOSLanguage struct {
+	Language  string
+	Territory string
+	Encoding  string
+}
+
+func (b BIOSLanguage) String() string {
+	s := b.Language
+	if b.Territory != "" {
+		s += "_" + b.Territory
+	}
+	if b.Encoding != "" {
+		s += "." + b.Encoding
+	}
+	return s
+}
+
+// ParseBIOSLanguage parses a language string in the long format, such as
+// en|US|iso8859-1, or the abbreviated format, such as enUS
+func ParseBIOSLanguage(s string, f BIOSLanguageInformationFlag) BIOSLanguage {
+	var b BIOSLanguage
+	if f == BIOSLanguageInformationFlagLongFormat || strings.Contains(s, "|") {
+		fields := strings.Split(s, "|")
+		b.Language = strings.ToLower(fields[0])
+		if len(fields) > 1 {
+			b.Territory = strings.ToUpper(fields[1])
+		}
+		if len(fields) > 2 {
+			b.Encoding = fields[2]
+		}
+		return b
+	}
+	s = strings.NewReplacer("-", "", "_", "").Replace(s)
+	if len(s) >= 2 {
+		b.Language = strings.ToLower(s[:2])
+	}
+	if len(s) >= 4 {
+		b.Territory = strings.ToUpper(s[2:4])
+	}
+	return b
 }
 
 type BIOSLanguageInformation struct {
@@ -28,25 +80,46 @@ type BIOSLanguageInformation struct {
 	CurrentLanguage     string
 }
 
+// Languages returns the installable languages parsed according to Flags
+func (b BIOSLanguageInformation) Languages() []BIOSLanguage {
+	var bls []BIOSLanguage
+	for _, l := range b.InstallableLanguage {
+		bls = append(bls, ParseBIOSLanguage(l, b.Flags))
+	}
+	return bls
+}
+
+// Current returns the current language parsed according to Flags
+func (b BIOSLanguageInformation) Current() BIOSLanguage {
+	return ParseBIOSLanguage(b.CurrentLanguage, b.Flags)
+}
+
 func (b BIOSLanguageInformation) String() string {
-	return fmt.Sprintf("BIOS Language Information:\n"+
-		"\tInstallable Languages %s\n"+
-		"\tFlags: %s\n"+
-		"\tCurrent Language: %s",
-		b.InstallableLanguage,
+	s := fmt.Sprintf("BIOS Language Information\n"+
+		"\tLanguage Description Format: %s\n"+
+		"\tInstallable Languages: %d",
 		b.Flags,
-		b.CurrentLanguage)
+		len(b.InstallableLanguage))
+	for _, l := range b.InstallableLanguage {
+		s += "\n\t\t" + l
+	}
+	s += fmt.Sprintf("\n\tCurrently Installed Language: %s", b.CurrentLanguage)
+	return s
 }
 
 func newBIOSLanguageInformation(h dmiHeader) dmiTyper {
 	var bl BIOSLanguageInformation
 	data := h.data
 	cnt := data[0x04]
-	for i := byte(1); i <= cnt; i++ {
-		bl.InstallableLanguage = append(bl.InstallableLanguage, h.FieldString(int(data[i])))
+	for i := 1; i <= int(cnt); i++ {
+		bl.InstallableLanguage = append(bl.InstallableLanguage, h.FieldString(i))
+	}
+	if h.Length > 0x05 {
+		bl.Flags = NewBIOSLanguageInformationFlag(data[0x05])
+	}
+	if h.Length > 0x15 {
+		bl.CurrentLanguage = h.FieldString(int(data[0x15]))
 	}
-	bl.Flags = NewBIOSLanguageInformationFlag(data[0x05])
-	bl.CurrentLanguage = bl.InstallableLanguage[data[0x15]]
 	return &bl
 }
 
@@ -0,0 +1,62 @@
+package godmi
+
+import "testing"
+
+func biosLanguage(handle uint16, flags, current byte, langs ...string) []byte {
+	b := make([]byte, 0x12)
+	b[0x00] = byte(len(langs))
+	b[0x01] = flags
+	b[0x11] = current
+	return structure(13, handle, b, langs...)
+}
+
+func TestNewBIOSLanguageInformation(t *testing.T) {
+	loadTable(biosLanguage(0x0D00, 0, 2, "en|US|iso8859-1", "fr|CA|iso8859-1"))
+	bl := GetBIOSLanguageInformation()
+	if bl == nil || len(bl.InstallableLanguage) != 2 || bl.Flags != BIOSLanguageInformationFlagLongFormat {
+		t.Fatalf("GetBIOSLanguageInformation: got %v", bl)
+	}
+	if c := bl.Current(); c != (BIOSLanguage{"fr", "CA", "iso8859-1"}) || c.String() != "fr_CA.iso8859-1" {
+		t.Errorf("Current: got %v", c)
+	}
+
+	// Only bit 0 of the flags is defined
+	loadTable(biosLanguage(0x0D00, 0xFF, 1, "enUS", "deDE"))
+	bl = GetBIOSLanguageInformation()
+	if bl.Flags != BIOSLanguageInformationFlagAbbreviatedFormat {
+		t.Errorf("Flags: got %v", bl.Flags)
+	}
+	if ls := bl.Languages(); len(ls) != 2 || ls[1] != (BIOSLanguage{"de", "DE", ""}) {
+		t.Errorf("Languages: got %v", ls)
+	}
+	if c := bl.Current(); c.String() != "en_US" {
+		t.Errorf("Current: got %v", c)
+	}
+
+	// An SMBIOS 2.0 structure ends after the count, before the flags and
+	// the current language
+	loadTable(structure(13, 0x0D00, []byte{1}, "en|US|iso8859-1"))
+	bl = GetBIOSLanguageInformation()
+	if len(bl.InstallableLanguage) != 1 || bl.Flags != BIOSLanguageInformationFlagLongFormat || bl.CurrentLanguage != "" {
+		t.Errorf("short structure: got %v", bl)
+	}
+}
+
+func TestParseBIOSLanguage(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		f    BIOSLanguageInformationFlag
+		want BIOSLanguage
+	}{
+		{"EN|us|iso8859-1", BIOSLanguageInformationFlagLongFormat, BIOSLanguage{"en", "US", "iso8859-1"}},
+		{"ja|JP", BIOSLanguageInformationFlagLongFormat, BIOSLanguage{"ja", "JP", ""}},
+		// A long string behind an abbreviated flag is still split at '|'
+		{"de|DE|utf-8", BIOSLanguageInformationFlagAbbreviatedFormat, BIOSLanguage{"de", "DE", "utf-8"}},
+		{"en-us", BIOSLanguageInformationFlagAbbreviatedFormat, BIOSLanguage{"en", "US", ""}},
+		{"zh", BIOSLanguageInformationFlagAbbreviatedFormat, BIOSLanguage{"zh", "", ""}},
+	} {
+		if b := ParseBIOSLanguage(c.s, c.f); b != c.want {
+			t.Errorf("ParseBIOSLanguage(%q, %s) = %v, want %v", c.s, c.f, b, c.want)
+		}
+	}
+}
@@ -21,12 +21,32 @@ type GroupAssociations struct {
 	Item      []GroupAssociationsItem
 }
 
+func (g GroupAssociationsItem) String() string {
+	return fmt.Sprintf("0x%04X (%s)", g.Handle, g.Type)
+}
+
 func (g GroupAssociations) String() string {
-	return fmt.Sprintf("Group Associations:\n"+
-		"\tGroup Name: %s\n"+
-		"\tItem: %#v\n",
+	s := fmt.Sprintf("Group Associations\n"+
+		"\tName: %s\n"+
+		"\tItems: %d",
 		g.GroupName,
-		g.Item)
+		len(g.Item))
+	for _, i := range g.Item {
+		s += "\n\t\t" + i.String()
+	}
+	return s
+}
+
+// Structures returns the decoded structures the items of the group refer
+// to, skipping handles that are not in the table or of an unknown type
+func (g GroupAssociations) Structures() []interface{} {
+	var ss []interface{}
+	for _, i := range g.Item {
+		if d := GetStructure(i.Handle); d != nil {
+			ss = append(ss, d)
+		}
+	}
+	return ss
 }
 
 func newGroupAssociations(h dmiHeader) dmiTyper {
@@ -52,6 +72,24 @@ func GetGroupAssociations() *GroupAssociations {
 	return nil
 }
 
+func GetGroupAssociationsList() []*GroupAssociations {
+	var gs []*GroupAssociations
+	for _, d := range GetStructures(SMBIOSStructureTypeGroupAssociations) {
+		gs = append(gs, d.(*GroupAssociations))
+	}
+	return gs
+}
+
+// GetGroupAssociationsByName returns the group called name, or nil
+func GetGroupAssociationsByName(name string) *GroupAssociations {
+	for _, g := range GetGroupAssociationsList() {
+		if g.GroupName == name {
+			return g
+		}
+	}
+	return nil
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeGroupAssociations, newGroupAssociations)
 }
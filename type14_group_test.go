@@ -0,0 +1,49 @@
+package godmi
+
+import "testing"
+
+func groupAssociations(handle uint16, name string, items ...GroupAssociationsItem) []byte {
+	b := []byte{1}
+	for _, i := range items {
+		b = append(b, byte(i.Type), byte(i.Handle), byte(i.Handle>>8))
+	}
+	return structure(14, handle, b, name)
+}
+
+func TestGroupAssociationsStructures(t *testing.T) {
+	loadTable(
+		groupAssociations(0x0E00, "Cpu Module",
+			GroupAssociationsItem{SMBIOSStructureTypeProcessor, 0x0400},
+			GroupAssociationsItem{SMBIOSStructureTypeSystemSlots, 0x0900},
+			// A handle that is not in the table is skipped
+			GroupAssociationsItem{SMBIOSStructureTypeCache, 0x0700}),
+		groupAssociations(0x0E01, "Firmware",
+			GroupAssociationsItem{SMBIOSStructureTypeBIOS, 0x0000}),
+		structure(0, 0x0000, make([]byte, 0x0E)),
+		structure(4, 0x0400, make([]byte, 0x26)),
+		structure(9, 0x0900, []byte{1, 0, 0, 0, 0, 0, 0, 0}, "PCIE1"))
+
+	gs := GetGroupAssociationsList()
+	if len(gs) != 2 || len(gs[0].Item) != 3 || len(gs[1].Item) != 1 {
+		t.Fatalf("GetGroupAssociationsList: got %v", gs)
+	}
+	if i := gs[0].Item[1]; i.Type != SMBIOSStructureTypeSystemSlots || i.Handle != 0x0900 {
+		t.Errorf("item 1: got %v", i)
+	}
+	ss := gs[0].Structures()
+	if len(ss) != 2 {
+		t.Fatalf("Structures: got %v", ss)
+	}
+	if p, ok := ss[0].(*ProcessorInformation); !ok || p.Handle != 0x0400 {
+		t.Errorf("Structures[0]: got %v", ss[0])
+	}
+	if s, ok := ss[1].(*SystemSlot); !ok || s.Designation != "PCIE1" {
+		t.Errorf("Structures[1]: got %v", ss[1])
+	}
+	if g := GetGroupAssociationsByName("Firmware"); g != gs[1] || len(g.Structures()) != 1 {
+		t.Errorf("GetGroupAssociationsByName: got %v", g)
+	}
+	if g := GetGroupAssociationsByName("Memory"); g != nil {
+		t.Errorf("GetGroupAssociationsByName of a missing group: got %v", g)
+	}
+}
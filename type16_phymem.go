@@ -149,6 +149,14 @@ func GetPhysicalMemoryArray() *PhysicalMemoryArray {
 	return nil
 }
 
+func GetPhysicalMemoryArrays() []*PhysicalMemoryArray {
+	var ps []*PhysicalMemoryArray
+	for _, d := range GetStructures(SMBIOSStructureTypePhysicalMemoryArray) {
+		ps = append(ps, d.(*PhysicalMemoryArray))
+	}
+	return ps
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypePhysicalMemoryArray, newPhysicalMemoryArray)
 }
@@ -48,7 +48,10 @@ func (m MemoryDeviceFormFactor) String() string {
 		"SRIMM",
 		"FB-DIMM",
 	}
-	return factors[m-1]
+	if m >= 1 && int(m) <= len(factors) {
+		return factors[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryDeviceType byte
@@ -105,7 +108,10 @@ func (m MemoryDeviceType) String() string {
 		"DDR3",
 		"FBD2",
 	}
-	return types[m-1]
+	if m >= 1 && int(m) <= len(types) {
+		return types[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryDeviceTypeDetail byte
@@ -148,7 +154,10 @@ func (m MemoryDeviceTypeDetail) String() string {
 		"Unbuffered (Unregistered)",
 		"LRDIMM",
 	}
-	return details[m-1]
+	if m >= 1 && int(m) <= len(details) {
+		return details[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryDevice struct {
@@ -195,8 +204,8 @@ func (m MemoryDevice) String() string {
 		"\tSerial Number: %s\n"+
 		"\tAsset Tag: %s\n"+
 		"\tPart Number: %s\n"+
-		"\tAttributes: %s\n"+
-		"\tExtended Size: %s\n"+
+		"\tAttributes: %d\n"+
+		"\tExtended Size: %d\n"+
 		"\tConfigured Memory Clock Speed: %d\n"+
 		"\tMinimum voltage: %d\n"+
 		"\tMaximum voltage: %d\n"+
@@ -211,6 +220,7 @@ func (m MemoryDevice) String() string {
 		m.DeviceLocator,
 		m.BankLocator,
 		m.Type,
+		m.TypeDetail,
 		m.Speed,
 		m.Manufacturer,
 		m.SerialNumber,
@@ -225,31 +235,66 @@ func (m MemoryDevice) String() string {
 	)
 }
 
+// Installed reports whether a memory device is present in the socket
+func (m MemoryDevice) Installed() bool {
+	return m.Size != 0
+}
+
+// SizeBytes returns the size of the memory device in bytes, or 0 if no
+// device is installed or the size is unknown
+func (m MemoryDevice) SizeBytes() uint64 {
+	switch {
+	case m.Size == 0 || m.Size == 0xFFFF:
+		return 0
+	case m.Size == 0x7FFF:
+		return uint64(m.ExtendedSize&0x7FFFFFFF) << 20
+	case m.Size&0x8000 != 0:
+		return uint64(m.Size&0x7FFF) << 10
+	}
+	return uint64(m.Size) << 20
+}
+
+// Rank returns the number of ranks of the memory device, or 0 if unknown
+func (m MemoryDevice) Rank() int {
+	return int(m.Attributes & 0x0F)
+}
+
 func newMemoryDevice(h dmiHeader) dmiTyper {
 	data := h.data
-	return &MemoryDevice{
-		PhysicalMemoryArrayHandle:  u16(data[0x04:0x06]),
-		ErrorInformationHandle:     u16(data[0x06:0x08]),
-		TotalWidth:                 u16(data[0x08:0x0A]),
-		DataWidth:                  u16(data[0x0A:0x0C]),
-		Size:                       u16(data[0x0C:0x0e]),
-		FormFactor:                 MemoryDeviceFormFactor(data[0x0E]),
-		DeviceSet:                  data[0x0F],
-		DeviceLocator:              h.FieldString(int(data[0x10])),
-		BankLocator:                h.FieldString(int(data[0x11])),
-		Type:                       MemoryDeviceType(data[0x12]),
-		TypeDetail:                 MemoryDeviceTypeDetail(u16(data[0x13:0x15])),
-		Speed:                      u16(data[0x15:0x17]),
-		Manufacturer:               h.FieldString(int(data[0x17])),
-		SerialNumber:               h.FieldString(int(data[0x18])),
-		PartNumber:                 h.FieldString(int(data[0x1A])),
-		Attributes:                 data[0x1B],
-		ExtendedSize:               u32(data[0x1C:0x20]),
-		ConfiguredMemoryClockSpeed: u16(data[0x20:0x22]),
-		MinimumVoltage:             u16(data[0x22:0x24]),
-		MaximumVoltage:             u16(data[0x24:0x26]),
-		ConfiguredVoltage:          u16(data[0x26:0x28]),
+	md := &MemoryDevice{
+		PhysicalMemoryArrayHandle: u16(data[0x04:0x06]),
+		ErrorInformationHandle:    u16(data[0x06:0x08]),
+		TotalWidth:                u16(data[0x08:0x0A]),
+		DataWidth:                 u16(data[0x0A:0x0C]),
+		Size:                      u16(data[0x0C:0x0e]),
+		FormFactor:                MemoryDeviceFormFactor(data[0x0E]),
+		DeviceSet:                 data[0x0F],
+		DeviceLocator:             h.FieldString(int(data[0x10])),
+		BankLocator:               h.FieldString(int(data[0x11])),
+		Type:                      MemoryDeviceType(data[0x12]),
+		TypeDetail:                MemoryDeviceTypeDetail(u16(data[0x13:0x15])),
+	}
+	// Fields added by later versions stay 0, which they all use for unknown
+	if h.Length >= 0x1B {
+		md.Speed = u16(data[0x15:0x17])
+		md.Manufacturer = h.FieldString(int(data[0x17]))
+		md.SerialNumber = h.FieldString(int(data[0x18]))
+		md.AssetTag = h.FieldString(int(data[0x19]))
+		md.PartNumber = h.FieldString(int(data[0x1A]))
+	}
+	if h.Length >= 0x1C {
+		md.Attributes = data[0x1B]
+	}
+	if h.Length >= 0x22 {
+		md.ExtendedSize = u32(data[0x1C:0x20])
+		md.ConfiguredMemoryClockSpeed = u16(data[0x20:0x22])
 	}
+	if h.Length >= 0x28 {
+		md.MinimumVoltage = u16(data[0x22:0x24])
+		md.MaximumVoltage = u16(data[0x24:0x26])
+		md.ConfiguredVoltage = u16(data[0x26:0x28])
+	}
+	return md
 }
 
 func GetMemoryDevice() *MemoryDevice {
@@ -259,6 +304,14 @@ func GetMemoryDevice() *MemoryDevice {
 	return nil
 }
 
+func GetMemoryDevices() []*MemoryDevice {
+	var mds []*MemoryDevice
+	for _, d := range GetStructures(SMBIOSStructureTypeMemoryDevice) {
+		mds = append(mds, d.(*MemoryDevice))
+	}
+	return mds
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeMemoryDevice, newMemoryDevice)
 }
@@ -46,7 +46,10 @@ func (m MemoryErrorInformationType) String() string {
 		"Corrected error",
 		"Uncorrectable error",
 	}
-	return types[m-1]
+	if m >= MemoryErrorInformationTypeOther && m <= MemoryErrorInformationTypeUncorrectableerror {
+		return types[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryErrorInformationGranularity byte
@@ -65,7 +68,10 @@ func (m MemoryErrorInformationGranularity) String() string {
 		"Device level",
 		"Memory partition level",
 	}
-	return grans[m-1]
+	if m >= MemoryErrorInformationGranularityOther && m <= MemoryErrorInformationGranularityMemorypartitionlevel {
+		return grans[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryErrorInformationOperation byte
@@ -86,29 +92,80 @@ func (m MemoryErrorInformationOperation) String() string {
 		"Write",
 		"Partial write",
 	}
-	return operations[m-1]
+	if m >= MemoryErrorInformationOperationOther && m <= MemoryErrorInformationOperationPartialwrite {
+		return operations[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
-type _32BitMemoryErrorInformation struct {
-	infoCommon
+// MemoryErrorAddress is a memory error address, or
+// MemoryErrorAddressUnknown if the address is not known
+type MemoryErrorAddress uint64
+
+const MemoryErrorAddressUnknown MemoryErrorAddress = 1 << 63
+
+func (m MemoryErrorAddress) Known() bool {
+	return m != MemoryErrorAddressUnknown
+}
+
+func (m MemoryErrorAddress) String() string {
+	if !m.Known() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("0x%08X", uint64(m))
+}
+
+func newMemoryErrorAddress32(u uint32) MemoryErrorAddress {
+	if u == 0x80000000 {
+		return MemoryErrorAddressUnknown
+	}
+	return MemoryErrorAddress(u)
+}
+
+// IsError reports whether the type records an actual error rather than
+// OK, Other or Unknown
+func (m MemoryErrorInformationType) IsError() bool {
+	return m > MemoryErrorInformationTypeOK && m <= MemoryErrorInformationTypeUncorrectableerror
+}
+
+// Special values of the Memory Error Information Handle of Physical
+// Memory Arrays and Memory Devices
+const (
+	MemoryErrorInformationHandleNotProvided uint16 = 0xFFFE
+	MemoryErrorInformationHandleNoError     uint16 = 0xFFFF
+)
+
+// MemoryErrorResolution is the range, in bytes, within which the error
+// can be determined
+type MemoryErrorResolution uint32
+
+func (m MemoryErrorResolution) String() string {
+	if m == 0x80000000 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d bytes", uint32(m))
+}
+
+// MemoryErrorInformation is what the 32-bit and 64-bit Memory Error
+// Information structures have in common
+type MemoryErrorInformation struct {
 	Type              MemoryErrorInformationType
 	Granularity       MemoryErrorInformationGranularity
 	Operation         MemoryErrorInformationOperation
 	VendorSyndrome    uint32
-	ArrayErrorAddress uint32
-	ErrorAddress      uint32
-	Resolution        uint32
+	ArrayErrorAddress MemoryErrorAddress
+	ErrorAddress      MemoryErrorAddress
+	Resolution        MemoryErrorResolution
 }
 
-func (m _32BitMemoryErrorInformation) String() string {
-	return fmt.Sprintf("32 Bit Memory Error Information\n"+
-		"\tError Type: %s\n"+
-		"\tError Granularity: %s\n"+
-		"\tError Operation: %s\n"+
-		"\tVendor Syndrome: %d\n"+
-		"\tMemory Array Error Address: %d\n"+
-		"\tDevice Error Address: %d\n"+
-		"\tError Resoluton: %d",
+func (m MemoryErrorInformation) String() string {
+	return fmt.Sprintf("\tType: %s\n"+
+		"\tGranularity: %s\n"+
+		"\tOperation: %s\n"+
+		"\tVendor Syndrome: 0x%08X\n"+
+		"\tMemory Array Address: %s\n"+
+		"\tDevice Address: %s\n"+
+		"\tResolution: %s",
 		m.Type,
 		m.Granularity,
 		m.Operation,
@@ -119,16 +176,27 @@ func (m _32BitMemoryErrorInformation) String() string {
 	)
 }
 
+type _32BitMemoryErrorInformation struct {
+	infoCommon
+	MemoryErrorInformation
+}
+
+func (m _32BitMemoryErrorInformation) String() string {
+	return "32-bit Memory Error Information\n" + m.MemoryErrorInformation.String()
+}
+
 func new_32BitMemoryErrorInformation(h dmiHeader) dmiTyper {
 	data := h.data
 	return &_32BitMemoryErrorInformation{
-		Type:              MemoryErrorInformationType(data[0x04]),
-		Granularity:       MemoryErrorInformationGranularity(data[0x05]),
-		Operation:         MemoryErrorInformationOperation(data[0x06]),
-		VendorSyndrome:    u32(data[0x07:0x0B]),
-		ArrayErrorAddress: u32(data[0x0B:0x0F]),
-		ErrorAddress:      u32(data[0x0F:0x13]),
-		Resolution:        u32(data[0x13:0x22]),
+		MemoryErrorInformation: MemoryErrorInformation{
+			Type:              MemoryErrorInformationType(data[0x04]),
+			Granularity:       MemoryErrorInformationGranularity(data[0x05]),
+			Operation:         MemoryErrorInformationOperation(data[0x06]),
+			VendorSyndrome:    u32(data[0x07:0x0B]),
+			ArrayErrorAddress: newMemoryErrorAddress32(u32(data[0x0B:0x0F])),
+			ErrorAddress:      newMemoryErrorAddress32(u32(data[0x0F:0x13])),
+			Resolution:        MemoryErrorResolution(u32(data[0x13:0x17])),
+		},
 	}
 }
 
@@ -139,6 +207,118 @@ func Get_32BitMemoryErrorInformation() *_32BitMemoryErrorInformation {
 	return nil
 }
 
+// MemoryErrorStatus tells what a Memory Error Information Handle says
+// about the errors of an array or device
+type MemoryErrorStatus byte
+
+const (
+	// MemoryErrorStatusNotProvided is for a handle of 0xFFFE, the firmware
+	// not reporting errors
+	MemoryErrorStatusNotProvided MemoryErrorStatus = iota
+	// MemoryErrorStatusNoError is for a handle of 0xFFFF, the firmware
+	// reporting that no error was detected
+	MemoryErrorStatusNoError
+	// MemoryErrorStatusReported is for a handle of a Memory Error
+	// Information structure
+	MemoryErrorStatusReported
+	// MemoryErrorStatusUnresolved is for a handle that is not in the table
+	MemoryErrorStatusUnresolved
+)
+
+func (m MemoryErrorStatus) String() string {
+	statuses := [...]string{
+		"No error information",
+		"No error",
+		"Reported",
+		"Error information not found",
+	}
+	if int(m) < len(statuses) {
+		return statuses[m]
+	}
+	return OUT_OF_SPEC
+}
+
+// getMemoryErrorInformation resolves a Memory Error Information Handle to
+// a 32-bit or 64-bit Memory Error Information structure
+func getMemoryErrorInformation(handle uint16) (*MemoryErrorInformation, MemoryErrorStatus) {
+	switch handle {
+	case MemoryErrorInformationHandleNotProvided:
+		return nil, MemoryErrorStatusNotProvided
+	case MemoryErrorInformationHandleNoError:
+		return nil, MemoryErrorStatusNoError
+	}
+	switch m := GetStructure(SMBIOSStructureHandle(handle)).(type) {
+	case *_32BitMemoryErrorInformation:
+		return &m.MemoryErrorInformation, MemoryErrorStatusReported
+	case *_64BitMemoryErrorInformation:
+		return &m.MemoryErrorInformation, MemoryErrorStatusReported
+	}
+	return nil, MemoryErrorStatusUnresolved
+}
+
+// MemoryHealth is the error recorded for a memory device, or for a whole
+// physical memory array when Device is nil
+type MemoryHealth struct {
+	Array  *PhysicalMemoryArray
+	Device *MemoryDevice
+	// Status tells whether the firmware reports errors at all. Error is
+	// only set when it is MemoryErrorStatusReported.
+	Status MemoryErrorStatus
+	Error  *MemoryErrorInformation
+}
+
+// HasError reports whether an actual error, such as a CRC, single-bit or
+// multi-bit error, is recorded
+func (m MemoryHealth) HasError() bool {
+	return m.Error != nil && m.Error.Type.IsError()
+}
+
+func (m MemoryHealth) String() string {
+	var s string
+	if m.Device != nil {
+		s = fmt.Sprintf("%s %s", m.Device.DeviceLocator, m.Device.BankLocator)
+	} else if m.Array != nil {
+		s = fmt.Sprintf("Physical Memory Array 0x%04X", m.Array.Handle)
+	}
+	if m.Error == nil {
+		return fmt.Sprintf("%s: %s", s, m.Status)
+	}
+	return fmt.Sprintf("%s: %s", s, m.Error.Type)
+}
+
+// GetMemoryHealth walks every physical memory array and memory device to
+// its memory error information
+func GetMemoryHealth() []MemoryHealth {
+	var mhs []MemoryHealth
+	arrays := make(map[uint16]*PhysicalMemoryArray)
+	for _, a := range GetPhysicalMemoryArrays() {
+		arrays[uint16(a.Handle)] = a
+		mh := MemoryHealth{Array: a}
+		mh.Error, mh.Status = getMemoryErrorInformation(a.ErrorInformationHandle)
+		mhs = append(mhs, mh)
+	}
+	for _, md := range GetMemoryDevices() {
+		mh := MemoryHealth{
+			Array:  arrays[md.PhysicalMemoryArrayHandle],
+			Device: md,
+		}
+		mh.Error, mh.Status = getMemoryErrorInformation(md.ErrorInformationHandle)
+		mhs = append(mhs, mh)
+	}
+	return mhs
+}
+
+// GetMemoryErrors returns the memory devices and arrays with a recorded error
+func GetMemoryErrors() []MemoryHealth {
+	var mhs []MemoryHealth
+	for _, mh := range GetMemoryHealth() {
+		if mh.HasError() {
+			mhs = append(mhs, mh)
+		}
+	}
+	return mhs
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureType32_bitMemoryError, new_32BitMemoryErrorInformation)
 }
@@ -0,0 +1,98 @@
+package godmi
+
+import "testing"
+
+func memoryError32(handle uint16, typ MemoryErrorInformationType, array, device uint32) []byte {
+	b := make([]byte, 0x13)
+	b[0x00] = byte(typ)
+	b[0x01] = byte(MemoryErrorInformationGranularityDevicelevel)
+	b[0x02] = byte(MemoryErrorInformationOperationRead)
+	put16(b, 0x03, 0xBEEF)
+	put16(b, 0x07, uint16(array))
+	put16(b, 0x09, uint16(array>>16))
+	put16(b, 0x0B, uint16(device))
+	put16(b, 0x0D, uint16(device>>16))
+	put16(b, 0x11, 0x8000)
+	return structure(18, handle, b)
+}
+
+func physicalMemoryArray(handle, errorHandle uint16) []byte {
+	b := make([]byte, 0x13)
+	put16(b, 0x07, errorHandle)
+	return structure(16, handle, b)
+}
+
+func memoryDeviceWithError(handle, array, errorHandle uint16, locator string) []byte {
+	b := memoryDevice(handle, array, locator)
+	put16(b, 0x06, errorHandle)
+	return b
+}
+
+func TestNew32BitMemoryErrorInformation(t *testing.T) {
+	loadTable(
+		memoryError32(0x1200, MemoryErrorInformationTypeCRCerror, 0x12345678, 0x80000000),
+		memoryError32(0x1201, MemoryErrorInformationTypeOK, 0x80000000, 0))
+	ss := GetStructures(SMBIOSStructureType32_bitMemoryError)
+	if len(ss) != 2 {
+		t.Fatalf("GetStructures: got %d", len(ss))
+	}
+	crc := ss[0].(*_32BitMemoryErrorInformation)
+	if crc.Type != MemoryErrorInformationTypeCRCerror || !crc.Type.IsError() ||
+		crc.Granularity != MemoryErrorInformationGranularityDevicelevel ||
+		crc.Operation != MemoryErrorInformationOperationRead || crc.VendorSyndrome != 0xBEEF ||
+		crc.ArrayErrorAddress != 0x12345678 || crc.ErrorAddress != MemoryErrorAddressUnknown ||
+		crc.ErrorAddress.String() != "Unknown" || crc.Resolution.String() != "Unknown" {
+		t.Errorf("CRC error: got %v", crc)
+	}
+	ok := ss[1].(*_32BitMemoryErrorInformation)
+	if ok.Type.IsError() || ok.ArrayErrorAddress.Known() || !ok.ErrorAddress.Known() ||
+		ok.ErrorAddress.String() != "0x00000000" {
+		t.Errorf("OK: got %v", ok)
+	}
+}
+
+func TestGetMemoryHealth(t *testing.T) {
+	loadTable(
+		physicalMemoryArray(0x1000, MemoryErrorInformationHandleNoError),
+		memoryDeviceWithError(0x1100, 0x1000, 0x1200, "DIMM_A1"),
+		memoryDeviceWithError(0x1101, 0x1000, 0x2100, "DIMM_A2"),
+		memoryDeviceWithError(0x1102, 0x1000, MemoryErrorInformationHandleNotProvided, "DIMM_B1"),
+		memoryDeviceWithError(0x1103, 0x1000, MemoryErrorInformationHandleNoError, "DIMM_B2"),
+		// A handle to a structure that is gone
+		memoryDeviceWithError(0x1104, 0x1000, 0x9999, "DIMM_C1"),
+		memoryError32(0x1200, MemoryErrorInformationTypeSingle_biterror, 0x80000000, 0x1000),
+		memoryError64(0x2100, MemoryErrorInformationTypeOK, 0x8000000000000000, 0x123456789A))
+
+	want := []struct {
+		locator string
+		status  MemoryErrorStatus
+		hasErr  bool
+		str     string
+	}{
+		{"", MemoryErrorStatusNoError, false, "Physical Memory Array 0x1000: No error"},
+		{"DIMM_A1", MemoryErrorStatusReported, true, "DIMM_A1 Not Specified: Single-bit error"},
+		{"DIMM_A2", MemoryErrorStatusReported, false, "DIMM_A2 Not Specified: OK"},
+		{"DIMM_B1", MemoryErrorStatusNotProvided, false, "DIMM_B1 Not Specified: No error information"},
+		{"DIMM_B2", MemoryErrorStatusNoError, false, "DIMM_B2 Not Specified: No error"},
+		{"DIMM_C1", MemoryErrorStatusUnresolved, false, "DIMM_C1 Not Specified: Error information not found"},
+	}
+	mhs := GetMemoryHealth()
+	if len(mhs) != len(want) {
+		t.Fatalf("GetMemoryHealth: got %v", mhs)
+	}
+	for i, w := range want {
+		mh := mhs[i]
+		var locator string
+		if mh.Device != nil {
+			locator = mh.Device.DeviceLocator
+		}
+		if locator != w.locator || mh.Array == nil || mh.Status != w.status ||
+			(mh.Error != nil) != (w.status == MemoryErrorStatusReported) ||
+			mh.HasError() != w.hasErr || mh.String() != w.str {
+			t.Errorf("health %d: got %v (%s), want %v", i, mh, mh.Status, w)
+		}
+	}
+	if es := GetMemoryErrors(); len(es) != 1 || es[0].Device.DeviceLocator != "DIMM_A1" {
+		t.Errorf("GetMemoryErrors: got %v", es)
+	}
+}
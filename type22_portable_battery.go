@@ -8,6 +8,7 @@ package godmi
 
 import (
 	"fmt"
+	"time"
 )
 
 type PortableBatteryDeviceChemistry byte
@@ -34,7 +35,61 @@ func (p PortableBatteryDeviceChemistry) String() string {
 		"Zinc air",
 		"Lithium Polymer",
 	}
-	return chems[p-1]
+	if p >= 1 && int(p) <= len(chems) {
+		return chems[p-1]
+	}
+	return OUT_OF_SPEC
+}
+
+// MilliwattHours is a battery capacity in mWh, 0 when unknown
+type MilliwattHours uint32
+
+func (m MilliwattHours) String() string {
+	if m == 0 {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d mWh", uint32(m))
+}
+
+// SBDSDate is a Smart Battery Data Specification date, packed as bits 15:9
+// year - 1980, bits 8:5 month and bits 4:0 day
+type SBDSDate uint16
+
+func (s SBDSDate) date() time.Time {
+	return time.Date(1980+int(s>>9), time.Month(s>>5&0x0F), int(s&0x1F), 0, 0, 0, 0, time.UTC)
+}
+
+// IsKnown reports whether s is set and a valid date. time.Date would turn
+// month 0 or day 31 of a shorter month into another date.
+func (s SBDSDate) IsKnown() bool {
+	t := s.date()
+	return s != 0 && int(t.Month()) == int(s>>5&0x0F) && t.Day() == int(s&0x1F)
+}
+
+func (s SBDSDate) Time() time.Time {
+	if !s.IsKnown() {
+		return time.Time{}
+	}
+	return s.date()
+}
+
+func (s SBDSDate) String() string {
+	if !s.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d-%02d-%02d", 1980+int(s>>9), s>>5&0x0F, s&0x1F)
+}
+
+// PortableBatteryMaximumErrorUnknown is the maximum error in battery data
+// of a battery that does not report it
+const PortableBatteryMaximumErrorUnknown = 0xFF
+
+// portableBatteryDateLayouts are the layouts of the manufacture date string,
+// the spec asking for MM/DD/YY
+var portableBatteryDateLayouts = []string{
+	"01/02/06",
+	"01/02/2006",
+	"2006-01-02",
 }
 
 type PortableBattery struct {
@@ -50,13 +105,51 @@ type PortableBattery struct {
 	SBDSVersionNumber         string
 	MaximumErrorInBatteryData byte
 	SBDSSerialNumber          uint16
-	SBDSManufactureDate       uint16
+	SBDSManufactureDate       SBDSDate
 	SBDSDeviceChemistry       string
 	DesignCapacityMultiplier  byte
 	OEMSepecific              uint32
 }
 
+// DesignCapacityMWh returns the design capacity scaled by its multiplier
+func (p PortableBattery) DesignCapacityMWh() MilliwattHours {
+	return MilliwattHours(uint32(p.DesignCapacity) * uint32(p.DesignCapacityMultiplier))
+}
+
+// ManufactureDate returns the date the battery was made. The date string is
+// used when it parses, and the SBDS date when the string is unspecified or
+// not a date.
+func (p PortableBattery) ManufactureDate() (time.Time, bool) {
+	for _, l := range portableBatteryDateLayouts {
+		if t, err := time.Parse(l, p.ManufacturerDate); err == nil {
+			return t, true
+		}
+	}
+	if p.SBDSManufactureDate.IsKnown() {
+		return p.SBDSManufactureDate.Time(), true
+	}
+	return time.Time{}, false
+}
+
+// Chemistry returns the device chemistry, from the SBDS string when the
+// chemistry byte leaves it unknown
+func (p PortableBattery) Chemistry() string {
+	if p.DeviceChemistry == PortableBatteryDeviceChemistryUnknown &&
+		p.SBDSDeviceChemistry != "" && p.SBDSDeviceChemistry != "Not Specified" {
+		return p.SBDSDeviceChemistry
+	}
+	return p.DeviceChemistry.String()
+}
+
 func (p PortableBattery) String() string {
+	voltage := "Unknown"
+	if p.DesignVoltage != 0 {
+		voltage = fmt.Sprintf("%d mV", p.DesignVoltage)
+	}
+	maxError := "Unknown"
+	if p.MaximumErrorInBatteryData != PortableBatteryMaximumErrorUnknown {
+		maxError = fmt.Sprintf("%d%%", p.MaximumErrorInBatteryData)
+	}
 	return fmt.Sprintf("Portable Battery\n"+
 		"\tLocation: %s\n"+
 		"\tManufacturer: %s\n"+
@@ -64,52 +157,57 @@ func (p PortableBattery) String() string {
 		"\tSerial Number: %s\n"+
 		"\tDevice Name: %s\n"+
 		"\tDevice Chemistry: %s\n"+
-		"\tDesign Capacity: %d\n"+
-		"\tDesign Voltage: %d\n"+
+		"\tDesign Capacity: %s\n"+
+		"\tDesign Voltage: %s\n"+
 		"\tSBDS Version Number: %s\n"+
-		"\tMaximum Error in Battery Data: %d\n"+
-		"\tSBDS Serial Numberd: %d\n"+
-		"\tSBDS Manufacturer Date: %d\n"+
+		"\tMaximum Error in Battery Data: %s\n"+
+		"\tSBDS Serial Number: %04X\n"+
+		"\tSBDS Manufacture Date: %s\n"+
 		"\tSBDS Device Chemistry: %s\n"+
-		"\tDesign Capacity Multiplier: %d\n"+
-		"\tOEM-specific: %d",
+		"\tOEM-specific Information: 0x%08X",
 		p.Location,
 		p.Manufacturer,
 		p.ManufacturerDate,
 		p.SerialNumber,
 		p.DeviceName,
 		p.DeviceChemistry,
-		p.DesignCapacity,
-		p.DesignVoltage,
+		p.DesignCapacityMWh(),
+		voltage,
 		p.SBDSVersionNumber,
-		p.MaximumErrorInBatteryData,
+		maxError,
 		p.SBDSSerialNumber,
 		p.SBDSManufactureDate,
 		p.SBDSDeviceChemistry,
-		p.DesignCapacityMultiplier,
 		p.OEMSepecific,
 	)
 }
 
 func newPortableBattery(h dmiHeader) dmiTyper {
 	data := h.data
-	return &PortableBattery{
-		Location:                  h.FieldString(int(data[0x04])),
-		Manufacturer:              h.FieldString(int(data[0x05])),
-		ManufacturerDate:          h.FieldString(int(data[0x06])),
-		SerialNumber:              h.FieldString(int(data[0x07])),
-		DeviceName:                h.FieldString(int(data[0x08])),
-		DeviceChemistry:           PortableBatteryDeviceChemistry(data[0x09]),
-		DesignCapacity:            u16(data[0x0A:0x0C]),
-		DesignVoltage:             u16(data[0x0C:0x0E]),
-		SBDSVersionNumber:         h.FieldString(int(data[0x0E])),
-		MaximumErrorInBatteryData: data[0x0F],
-		SBDSSerialNumber:          u16(data[0x10:0x12]),
-		SBDSManufactureDate:       u16(data[0x12:0x14]),
-		SBDSDeviceChemistry:       h.FieldString(int(data[0x14])),
-		DesignCapacityMultiplier:  data[0x15],
-		OEMSepecific:              u32(data[0x16:0x1A]),
+	pb := &PortableBattery{
+		Location:                 h.FieldString(int(data[0x04])),
+		Manufacturer:             h.FieldString(int(data[0x05])),
+		ManufacturerDate:         h.FieldString(int(data[0x06])),
+		SerialNumber:             h.FieldString(int(data[0x07])),
+		DeviceName:               h.FieldString(int(data[0x08])),
+		DeviceChemistry:          PortableBatteryDeviceChemistry(data[0x09]),
+		DesignCapacity:           u16(data[0x0A:0x0C]),
+		DesignVoltage:            u16(data[0x0C:0x0E]),
+		SBDSVersionNumber:        h.FieldString(int(data[0x0E])),
+		DesignCapacityMultiplier: 1,
+	}
+	pb.MaximumErrorInBatteryData = PortableBatteryMaximumErrorUnknown
+	if h.Length >= 0x10 {
+		pb.MaximumErrorInBatteryData = data[0x0F]
 	}
+	if h.Length >= 0x1A {
+		pb.SBDSSerialNumber = u16(data[0x10:0x12])
+		pb.SBDSManufactureDate = SBDSDate(u16(data[0x12:0x14]))
+		pb.SBDSDeviceChemistry = h.FieldString(int(data[0x14]))
+		pb.DesignCapacityMultiplier = data[0x15]
+		pb.OEMSepecific = u32(data[0x16:0x1A])
+	}
+	return pb
 }
 
 func GetPortableBattery() *PortableBattery {
@@ -119,6 +217,14 @@ func GetPortableBattery() *PortableBattery {
 	return nil
 }
 
+func GetPortableBatteries() []*PortableBattery {
+	var ps []*PortableBattery
+	for _, d := range GetStructures(SMBIOSStructureTypePortableBattery) {
+		ps = append(ps, d.(*PortableBattery))
+	}
+	return ps
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypePortableBattery, newPortableBattery)
 }
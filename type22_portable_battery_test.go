@@ -0,0 +1,81 @@
+package godmi
+
+import (
+	"strings"
+	"testing"
+)
+
+func sbdsDate(year, month, day int) SBDSDate {
+	return SBDSDate((year-1980)<<9 | month<<5 | day)
+}
+
+func TestSBDSDate(t *testing.T) {
+	for _, tc := range []struct {
+		d    SBDSDate
+		want string
+	}{
+		{sbdsDate(2026, 10, 18), "2026-10-18"},
+		{sbdsDate(2024, 2, 29), "2024-02-29"},
+		{sbdsDate(1980, 1, 1), "1980-01-01"},
+		{0, "Unknown"},
+		{sbdsDate(2026, 0, 10), "Unknown"},
+		{sbdsDate(2026, 13, 10), "Unknown"},
+		{sbdsDate(2026, 15, 10), "Unknown"},
+		{sbdsDate(2026, 10, 0), "Unknown"},
+		{sbdsDate(2026, 4, 31), "Unknown"},
+		{sbdsDate(2025, 2, 29), "Unknown"},
+	} {
+		if got := tc.d.String(); got != tc.want {
+			t.Errorf("SBDSDate(0x%04X).String() = %q, want %q", uint16(tc.d), got, tc.want)
+		}
+		if tc.want == "Unknown" {
+			if tc.d.IsKnown() || !tc.d.Time().IsZero() {
+				t.Errorf("SBDSDate(0x%04X): known as %v", uint16(tc.d), tc.d.Time())
+			}
+		} else if got := tc.d.Time().Format("2006-01-02"); got != tc.want {
+			t.Errorf("SBDSDate(0x%04X).Time() = %s, want %s", uint16(tc.d), got, tc.want)
+		}
+	}
+}
+
+func TestPortableBatteryManufactureDate(t *testing.T) {
+	for _, tc := range []struct {
+		str  string
+		sbds SBDSDate
+		want string
+	}{
+		{"10/18/26", 0, "2026-10-18"},
+		{"10/18/2026", 0, "2026-10-18"},
+		{"2026-10-18", sbdsDate(2020, 1, 1), "2026-10-18"},
+		{"Not Specified", sbdsDate(2020, 1, 1), "2020-01-01"},
+		{"Not Specified", sbdsDate(2026, 0, 10), ""},
+		{"soon", 0, ""},
+	} {
+		p := PortableBattery{ManufacturerDate: tc.str, SBDSManufactureDate: tc.sbds}
+		d, ok := p.ManufactureDate()
+		if got := map[bool]string{true: d.Format("2006-01-02")}[ok]; got != tc.want {
+			t.Errorf("ManufactureDate(%q, 0x%04X) = %q, want %q", tc.str, uint16(tc.sbds), got, tc.want)
+		}
+	}
+}
+
+func TestPortableBatteryMaximumError(t *testing.T) {
+	body := make([]byte, 0x16)
+	put16(body, 0x06, 5000)
+	for _, tc := range []struct {
+		n    int
+		err  byte
+		want string
+	}{
+		{0x0B, 0, "Maximum Error in Battery Data: Unknown"},
+		{0x0C, 0xFF, "Maximum Error in Battery Data: Unknown"},
+		{0x0C, 3, "Maximum Error in Battery Data: 3%"},
+	} {
+		body[0x0B] = tc.err
+		loadTable(structure(22, 0x22, body[:tc.n]))
+		p := GetPortableBattery()
+		if p == nil || !strings.Contains(p.String(), tc.want) {
+			t.Errorf("length 0x%02X, error %d: got %v", tc.n+4, tc.err, p)
+		}
+	}
+}
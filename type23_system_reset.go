@@ -8,6 +8,7 @@ package godmi
 
 import (
 	"fmt"
+	"time"
 )
 
 type SystemResetBootOption byte
@@ -26,57 +27,124 @@ func (s SystemResetBootOption) String() string {
 		"System Utilities",
 		"Do Not Reboot",
 	}
-	return options[s]
+	if int(s) < len(options) {
+		return options[s]
+	}
+	return OUT_OF_SPEC
 }
 
 type SystemResetCapabilities struct {
-	Status            bool
-	BootOptionOnLimit SystemResetBootOption
+	// Enabled reports whether the system reset is enabled by the user
+	Enabled           bool
 	BootOption        SystemResetBootOption
-	WatchdogTimer     bool
+	BootOptionOnLimit SystemResetBootOption
+	// WatchdogTimer reports whether the system contains a watchdog timer
+	WatchdogTimer bool
 }
 
 func NewSystemResetCapablities(data byte) SystemResetCapabilities {
-	var s SystemResetCapabilities
-	s.Status = (data&0x01 != 0)
-	s.BootOption = SystemResetBootOption(data & 0x06)
-	s.BootOptionOnLimit = SystemResetBootOption(data & 0x18)
-	s.WatchdogTimer = data&0x20 != 0
-	return s
+	return SystemResetCapabilities{
+		Enabled:           data&0x01 != 0,
+		BootOption:        SystemResetBootOption(data >> 1 & 0x03),
+		BootOptionOnLimit: SystemResetBootOption(data >> 3 & 0x03),
+		WatchdogTimer:     data&0x20 != 0,
+	}
 }
 
 func (s SystemResetCapabilities) String() string {
-	return fmt.Sprintf("Capablities\n"+
-		"\tStatus: %t\n"+
-		"\tBoot Option: %s\n"+
-		"\tBoot Option On Limit: %s\n"+
-		"\tWatchdog Timer: %t",
-		s.Status,
+	watchdog := "Not Present"
+	if s.WatchdogTimer {
+		watchdog = "Present"
+	}
+	return fmt.Sprintf("\n\t\tStatus: %s"+
+		"\n\t\tWatchdog Timer: %s"+
+		"\n\t\tBoot Option: %s"+
+		"\n\t\tBoot Option On Limit: %s",
+		enabled(s.Enabled),
+		watchdog,
 		s.BootOption,
-		s.BootOptionOnLimit,
-		s.WatchdogTimer)
+		s.BootOptionOnLimit)
+}
+
+// systemResetUnknown is the value of a count or time the firmware does not
+// know
+const systemResetUnknown = 0xFFFF
+
+// SystemResetDurationUnknown is the TimerInterval or Timeout of a system
+// that does not report it
+const SystemResetDurationUnknown time.Duration = -1
+
+type SystemResetCount uint16
+
+func (s SystemResetCount) IsKnown() bool {
+	return s != systemResetUnknown
+}
+
+func (s SystemResetCount) String() string {
+	if !s.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d", uint16(s))
+}
+
+func systemResetMinutes(data []byte) time.Duration {
+	m := u16(data)
+	if m == systemResetUnknown {
+		return SystemResetDurationUnknown
+	}
+	return time.Duration(m) * time.Minute
+}
+
+func systemResetDuration(d time.Duration) string {
+	if d == SystemResetDurationUnknown {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d min", int64(d/time.Minute))
 }
 
 type SystemReset struct {
 	infoCommon
-	Capabilities  byte
-	ResetCount    uint16
-	ResetLimit    uint16
-	TimerInterval uint16
-	Timeout       uint16
+	Capabilities SystemResetCapabilities
+	ResetCount   SystemResetCount
+	ResetLimit   SystemResetCount
+	// TimerInterval is the period of the watchdog timer
+	TimerInterval time.Duration
+	// Timeout is the time before the reset count is cleared
+	Timeout time.Duration
 }
 
 func (s SystemReset) String() string {
 	return fmt.Sprintf("System Reset\n"+
 		"\tCapabilities: %s\n"+
-		"\tReset Count: %d\n"+
-		"\tReset Limit: %d\n"+
-		"\tTimer Interval: %d\n"+
-		"\tTimeout: %d",
+		"\tReset Count: %s\n"+
+		"\tReset Limit: %s\n"+
+		"\tTimer Interval: %s\n"+
+		"\tTimeout: %s",
 		s.Capabilities,
 		s.ResetCount,
 		s.ResetLimit,
-		s.TimerInterval,
-		s.Timeout)
+		systemResetDuration(s.TimerInterval),
+		systemResetDuration(s.Timeout))
 }
 
+func newSystemReset(h dmiHeader) dmiTyper {
+	data := h.data
+	return &SystemReset{
+		Capabilities:  NewSystemResetCapablities(data[0x04]),
+		ResetCount:    SystemResetCount(u16(data[0x05:0x07])),
+		ResetLimit:    SystemResetCount(u16(data[0x07:0x09])),
+		TimerInterval: systemResetMinutes(data[0x09:0x0B]),
+		Timeout:       systemResetMinutes(data[0x0B:0x0D]),
+	}
+}
+
+func GetSystemReset() *SystemReset {
+	if d, ok := gdmi[SMBIOSStructureTypeSystemReset]; ok {
+		return d.(*SystemReset)
+	}
+	return nil
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeSystemReset, newSystemReset)
+}
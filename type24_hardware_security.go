@@ -26,7 +26,10 @@ func (h HardwareSecurityStatus) String() string {
 		"Not Implemented",
 		"Unknown",
 	}
-	return status[h]
+	if int(h) < len(status) {
+		return status[h]
+	}
+	return OUT_OF_SPEC
 }
 
 type HardwareSecuritySettings struct {
@@ -38,18 +41,18 @@ type HardwareSecuritySettings struct {
 
 func NewHardwareSecurity(data byte) HardwareSecuritySettings {
 	var h HardwareSecuritySettings
-	h.PowerOnPassword = HardwareSecurityStatus(data & 0xC0)
-	h.KeyboardPassword = HardwareSecurityStatus(data & 0x30)
-	h.AdministratorPassword = HardwareSecurityStatus(data & 0x0C)
+	h.PowerOnPassword = HardwareSecurityStatus(data >> 6 & 0x03)
+	h.KeyboardPassword = HardwareSecurityStatus(data >> 4 & 0x03)
+	h.AdministratorPassword = HardwareSecurityStatus(data >> 2 & 0x03)
 	h.FrontPanelReset = HardwareSecurityStatus(data & 0x03)
 	return h
 }
 
 func (h HardwareSecuritySettings) String() string {
-	return fmt.Sprintf("Power-on Password Status: %s\n"+
-		"Keyboard Password Status: %s\n"+
-		"Administrator Password Status: %s\n"+
-		"Front Panel Reset Status: %s\n",
+	return fmt.Sprintf("\n\t\tPower-On Password Status: %s"+
+		"\n\t\tKeyboard Password Status: %s"+
+		"\n\t\tAdministrator Password Status: %s"+
+		"\n\t\tFront Panel Reset Status: %s",
 		h.PowerOnPassword,
 		h.KeyboardPassword,
 		h.AdministratorPassword,
@@ -63,6 +66,31 @@ type HardwareSecurity struct {
 
 func (h HardwareSecurity) String() string {
 	return fmt.Sprintf("Hardware Security\n"+
-		"\tSetting: %s\n",
+		"\tSetting: %s",
 		h.Setting)
 }
+
+func newHardwareSecurity(h dmiHeader) dmiTyper {
+	return &HardwareSecurity{
+		Setting: NewHardwareSecurity(h.data[0x04]),
+	}
+}
+
+func GetHardwareSecurity() *HardwareSecurity {
+	if d, ok := gdmi[SMBIOSStructureTypeHardwareSecurity]; ok {
+		return d.(*HardwareSecurity)
+	}
+	return nil
+}
+
+func GetHardwareSecurities() []*HardwareSecurity {
+	var hs []*HardwareSecurity
+	for _, d := range GetStructures(SMBIOSStructureTypeHardwareSecurity) {
+		hs = append(hs, d.(*HardwareSecurity))
+	}
+	return hs
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeHardwareSecurity, newHardwareSecurity)
+}
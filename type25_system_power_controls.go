@@ -8,33 +8,135 @@ package godmi
 
 import (
 	"fmt"
+	"time"
 )
 
-type SystemPowerControlsMonth byte
-type SystemPowerControlsDayOfMonth byte
-type SystemPowerControlsHour byte
-type SystemPowerControlsMinute byte
-type SystemPowerControlsSecond byte
+// SystemPowerControlsAny is the value of a power-on field that matches any
+// value
+const SystemPowerControlsAny = -1
+
+// systemPowerControlsField decodes a BCD field, anything outside [min, max]
+// being a wildcard
+func systemPowerControlsField(data byte, min, max int) int {
+	if data>>4 > 9 || data&0x0F > 9 {
+		return SystemPowerControlsAny
+	}
+	v := int(bcd([]byte{data}))
+	if v < min || v > max {
+		return SystemPowerControlsAny
+	}
+	return v
+}
+
+func systemPowerControlsFieldString(v int) string {
+	if v == SystemPowerControlsAny {
+		return "*"
+	}
+	return fmt.Sprintf("%02d", v)
+}
 
 type SystemPowerControls struct {
 	infoCommon
-	NextScheduledPowerOnMonth      SystemPowerControlsMonth
-	NextScheduledPowerOnDayOfMonth SystemPowerControlsDayOfMonth
-	NextScheduledPowerOnHour       SystemPowerControlsHour
-	NextScheduledPowerMinute       SystemPowerControlsMinute
-	NextScheduledPowerSecond       SystemPowerControlsSecond
+	// The next scheduled power-on, each field SystemPowerControlsAny when
+	// it matches any value
+	Month      int
+	DayOfMonth int
+	Hour       int
+	Minute     int
+	Second     int
+}
+
+// scheduleTimeOfDay returns the earliest time of day not before from that
+// matches want, where negative wants match anything
+func scheduleTimeOfDay(want, from [3]int) ([3]int, bool) {
+	limits := [3]int{24, 60, 60}
+	var t [3]int
+	var walk func(i int, bounded bool) bool
+	walk = func(i int, bounded bool) bool {
+		if i == len(t) {
+			return true
+		}
+		start := 0
+		if bounded {
+			start = from[i]
+		}
+		for v := start; v < limits[i]; v++ {
+			if want[i] >= 0 && v != want[i] {
+				continue
+			}
+			t[i] = v
+			if walk(i+1, bounded && v == from[i]) {
+				return true
+			}
+		}
+		return false
+	}
+	return t, walk(0, true)
+}
+
+// NextScheduledPowerOn returns the first time after now that matches the
+// power-on schedule, in the location of now. It returns false when every
+// field is a wildcard, which schedules nothing, or when no date matches,
+// such as the 31st of a month without one.
+func (s SystemPowerControls) NextScheduledPowerOn(now time.Time) (time.Time, bool) {
+	if s.Month == SystemPowerControlsAny && s.DayOfMonth == SystemPowerControlsAny &&
+		s.Hour == SystemPowerControlsAny && s.Minute == SystemPowerControlsAny &&
+		s.Second == SystemPowerControlsAny {
+		return time.Time{}, false
+	}
+	want := [3]int{s.Hour, s.Minute, s.Second}
+	from := now.Truncate(time.Second).Add(time.Second)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, now.Location())
+	// Eight years cover a leap day that a century year skips
+	for i := 0; i < 8*366; i++ {
+		d := day.AddDate(0, 0, i)
+		if s.Month != SystemPowerControlsAny && int(d.Month()) != s.Month {
+			continue
+		}
+		if s.DayOfMonth != SystemPowerControlsAny && d.Day() != s.DayOfMonth {
+			continue
+		}
+		var lo [3]int
+		if i == 0 {
+			lo = [3]int{from.Hour(), from.Minute(), from.Second()}
+		}
+		t, ok := scheduleTimeOfDay(want, lo)
+		if !ok {
+			continue
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), t[0], t[1], t[2], 0, now.Location()), true
+	}
+	return time.Time{}, false
 }
 
 func (s SystemPowerControls) String() string {
 	return fmt.Sprintf("System Power Controls\n"+
-		"\tNext Scheduled Power-on Month: %d"+
-		"\tNext Scheduled Power-on Day-of-month: %d"+
-		"\tNext Scheduled Power-on Hour: %d"+
-		"\tNext Scheduled Power-on Minute: %d"+
-		"\tNext Scheduled Power-on Second: %d",
-		s.NextScheduledPowerOnMonth,
-		s.NextScheduledPowerOnDayOfMonth,
-		s.NextScheduledPowerOnHour,
-		s.NextScheduledPowerMinute,
-		s.NextScheduledPowerSecond)
+		"\tNext Scheduled Power-on: %s-%s %s:%s:%s",
+		systemPowerControlsFieldString(s.Month),
+		systemPowerControlsFieldString(s.DayOfMonth),
+		systemPowerControlsFieldString(s.Hour),
+		systemPowerControlsFieldString(s.Minute),
+		systemPowerControlsFieldString(s.Second))
+}
+
+func newSystemPowerControls(h dmiHeader) dmiTyper {
+	data := h.data
+	return &SystemPowerControls{
+		Month:      systemPowerControlsField(data[0x04], 1, 12),
+		DayOfMonth: systemPowerControlsField(data[0x05], 1, 31),
+		Hour:       systemPowerControlsField(data[0x06], 0, 23),
+		Minute:     systemPowerControlsField(data[0x07], 0, 59),
+		Second:     systemPowerControlsField(data[0x08], 0, 59),
+	}
+}
+
+func GetSystemPowerControls() *SystemPowerControls {
+	if d, ok := gdmi[SMBIOSStructureTypeSystemPowerControls]; ok {
+		return d.(*SystemPowerControls)
+	}
+	return nil
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeSystemPowerControls, newSystemPowerControls)
 }
@@ -0,0 +1,59 @@
+package godmi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextScheduledPowerOn(t *testing.T) {
+	const x = SystemPowerControlsAny
+	now := time.Date(2026, 10, 18, 10, 30, 15, 500, time.UTC)
+	at := func(y int, m time.Month, d, hh, mm, ss int) time.Time {
+		return time.Date(y, m, d, hh, mm, ss, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		s    SystemPowerControls
+		want time.Time
+		ok   bool
+	}{
+		{SystemPowerControls{Month: 12, DayOfMonth: 25, Hour: 8, Minute: 0, Second: 0}, at(2026, 12, 25, 8, 0, 0), true},
+		{SystemPowerControls{Month: x, DayOfMonth: x, Hour: 9, Minute: 0, Second: 0}, at(2026, 10, 19, 9, 0, 0), true},
+		{SystemPowerControls{Month: x, DayOfMonth: x, Hour: x, Minute: 0, Second: 0}, at(2026, 10, 18, 11, 0, 0), true},
+		// The schedule must lie after now, not at it
+		{SystemPowerControls{Month: x, DayOfMonth: x, Hour: x, Minute: x, Second: 15}, at(2026, 10, 18, 10, 31, 15), true},
+		{SystemPowerControls{Month: 10, DayOfMonth: 18, Hour: 10, Minute: 30, Second: 15}, at(2027, 10, 18, 10, 30, 15), true},
+		{SystemPowerControls{Month: x, DayOfMonth: 31, Hour: 0, Minute: 0, Second: 0}, at(2026, 10, 31, 0, 0, 0), true},
+		{SystemPowerControls{Month: 2, DayOfMonth: 29, Hour: 0, Minute: 0, Second: 0}, at(2028, 2, 29, 0, 0, 0), true},
+		{SystemPowerControls{Month: 2, DayOfMonth: 30, Hour: 0, Minute: 0, Second: 0}, time.Time{}, false},
+		{SystemPowerControls{Month: x, DayOfMonth: x, Hour: x, Minute: x, Second: x}, time.Time{}, false},
+	} {
+		got, ok := tc.s.NextScheduledPowerOn(now)
+		if ok != tc.ok || !got.Equal(tc.want) {
+			t.Errorf("%s: got %v %t, want %v %t", tc.s, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestNextScheduledPowerOnYearEnd(t *testing.T) {
+	now := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
+	s := SystemPowerControls{Month: SystemPowerControlsAny, DayOfMonth: 1, Hour: SystemPowerControlsAny,
+		Minute: SystemPowerControlsAny, Second: SystemPowerControlsAny}
+	got, ok := s.NextScheduledPowerOn(now)
+	if want := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC); !ok || !got.Equal(want) {
+		t.Errorf("got %v %t, want %v", got, ok, want)
+	}
+}
+
+func TestSystemPowerControlsDecode(t *testing.T) {
+	loadTable(structure(25, 0x25, []byte{0x12, 0x25, 0x08, 0xFF, 0x60}))
+	s := GetSystemPowerControls()
+	want := SystemPowerControls{Month: 12, DayOfMonth: 25, Hour: 8,
+		Minute: SystemPowerControlsAny, Second: SystemPowerControlsAny}
+	want.infoCommon = infoCommon{SMType: SMBIOSStructureTypeSystemPowerControls, Length: 9, Handle: 0x25}
+	if s == nil || *s != want {
+		t.Fatalf("GetSystemPowerControls: got %+v", s)
+	}
+	if got := s.String(); got != "System Power Controls\n\tNext Scheduled Power-on: 12-25 08:*:*" {
+		t.Errorf("String: got %q", got)
+	}
+}
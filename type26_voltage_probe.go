@@ -1,6 +1,6 @@
 /*
 * File Name:	type26_voltage_probe.go
-* Description:	
+* Description:
 * Author:	Chapman Ou <ochapman.cn@gmail.com>
 * Created:	2014-08-19
 */
@@ -10,18 +10,33 @@ import (
 	"fmt"
 )
 
-type VoltageProbeStatus byte
+// probeValueUnknown is the value of a probe field the firmware does not know
+const probeValueUnknown = 0x8000
+
+// probeInRange reports whether v lies within min and max. A limit the
+// firmware does not know does not restrict the range.
+func probeInRange(v, min, max int16) bool {
+	if uint16(min) != probeValueUnknown && v < min {
+		return false
+	}
+	if uint16(max) != probeValueUnknown && v > max {
+		return false
+	}
+	return true
+}
+
+type ProbeStatus byte
 
 const (
-	VoltageProbeStatusOther VoltageProbeStatus = 0x20 + iota
-	VoltageProbeStatusUnknown
-	VoltageProbeStatusOK
-	VoltageProbeStatusNon_critical
-	VoltageProbeStatusCritical
-	VoltageProbeStatusNon_recoverable
+	ProbeStatusOther ProbeStatus = 1 + iota
+	ProbeStatusUnknown
+	ProbeStatusOK
+	ProbeStatusNon_critical
+	ProbeStatusCritical
+	ProbeStatusNon_recoverable
 )
 
-func (v VoltageProbeStatus) String() string {
+func (p ProbeStatus) String() string {
 	status := [...]string{
 		"Other",
 		"Unknown",
@@ -30,7 +45,44 @@ func (v VoltageProbeStatus) String() string {
 		"Critical",
 		"Non-recoverable",
 	}
-	return status[v-6]
+	if p >= 1 && int(p) <= len(status) {
+		return status[p-1]
+	}
+	return OUT_OF_SPEC
+}
+
+// ProbeAccuracy is the accuracy of a probe reading in 1/100 %
+type ProbeAccuracy uint16
+
+func (p ProbeAccuracy) IsKnown() bool {
+	return p != probeValueUnknown
+}
+
+// Percent returns the accuracy in percent
+func (p ProbeAccuracy) Percent() float64 {
+	return float64(p) / 100
+}
+
+func (p ProbeAccuracy) String() string {
+	if !p.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.2f%%", p.Percent())
+}
+
+type VoltageProbeStatus ProbeStatus
+
+const (
+	VoltageProbeStatusOther           = VoltageProbeStatus(ProbeStatusOther)
+	VoltageProbeStatusUnknown         = VoltageProbeStatus(ProbeStatusUnknown)
+	VoltageProbeStatusOK              = VoltageProbeStatus(ProbeStatusOK)
+	VoltageProbeStatusNon_critical    = VoltageProbeStatus(ProbeStatusNon_critical)
+	VoltageProbeStatusCritical        = VoltageProbeStatus(ProbeStatusCritical)
+	VoltageProbeStatusNon_recoverable = VoltageProbeStatus(ProbeStatusNon_recoverable)
+)
+
+func (v VoltageProbeStatus) String() string {
+	return ProbeStatus(v).String()
 }
 
 type VoltageProbeLocation byte
@@ -38,10 +90,10 @@ type VoltageProbeLocation byte
 const (
 	VoltageProbeLocationOther VoltageProbeLocation = 1 + iota
 	VoltageProbeLocationUnknown
-	VoltageProbeLocationOK
-	VoltageProbeLocationNon_critical
-	VoltageProbeLocationCritical
-	VoltageProbeLocationNon_recoverable
+	VoltageProbeLocationProcessor
+	VoltageProbeLocationDisk
+	VoltageProbeLocationPeripheralBay
+	VoltageProbeLocationSystemManagementModule
 	VoltageProbeLocationMotherboard
 	VoltageProbeLocationMemoryModule
 	VoltageProbeLocationProcessorModule
@@ -53,17 +105,20 @@ func (v VoltageProbeLocation) String() string {
 	locations := [...]string{
 		"Other",
 		"Unknown",
-		"OK",
-		"Non-critical",
-		"Critical",
-		"Non-recoverable",
+		"Processor",
+		"Disk",
+		"Peripheral Bay",
+		"System Management Module",
 		"Motherboard",
 		"Memory Module",
 		"Processor Module",
 		"Power Unit",
 		"Add-in Card",
 	}
-	return locations[v-1]
+	if v >= 1 && int(v) <= len(locations) {
+		return locations[v-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type VoltageProbeLocationAndStatus struct {
@@ -73,47 +128,125 @@ type VoltageProbeLocationAndStatus struct {
 
 func NewVoltageProbeLocationAndStatus(data byte) VoltageProbeLocationAndStatus {
 	return VoltageProbeLocationAndStatus{
-		Status:   VoltageProbeStatus(data & 0x1F),
-		Location: VoltageProbeLocation(data & 0xE0),
+		Status:   VoltageProbeStatus(data >> 5),
+		Location: VoltageProbeLocation(data & 0x1F),
 	}
 }
 
-func (v VoltageProbeLocationAndStatus) String() string {
-	return fmt.Sprintf("\n\t\t\t\tStatus: %s\n\t\t\t\tLocation: %s",
-		v.Status, v.Location)
+// Millivolts is a voltage probe value in mV
+type Millivolts int16
+
+func (m Millivolts) IsKnown() bool {
+	return uint16(m) != probeValueUnknown
+}
+
+// Volts returns the value in V
+func (m Millivolts) Volts() float64 {
+	return float64(m) / 1000
+}
+
+func (m Millivolts) String() string {
+	if !m.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.3f V", m.Volts())
+}
+
+// VoltageProbeResolution is the resolution of a voltage probe in 1/10 mV
+type VoltageProbeResolution uint16
+
+func (v VoltageProbeResolution) IsKnown() bool {
+	return v != probeValueUnknown
+}
+
+// Millivolts returns the resolution in mV
+func (v VoltageProbeResolution) Millivolts() float64 {
+	return float64(v) / 10
+}
+
+func (v VoltageProbeResolution) String() string {
+	if !v.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.1f mV", v.Millivolts())
 }
 
 type VoltageProbe struct {
 	infoCommon
 	Description       string
 	LocationAndStatus VoltageProbeLocationAndStatus
-	MaximumValue      uint16
-	MinimumValude     uint16
-	Resolution        uint16
-	Tolerance         uint16
-	Accuracy          uint16
-	OEMdefined        uint16
-	NominalValue      uint16
+	MaximumValue      Millivolts
+	MinimumValue      Millivolts
+	Resolution        VoltageProbeResolution
+	Tolerance         Millivolts
+	Accuracy          ProbeAccuracy
+	OEMdefined        uint32
+	NominalValue      Millivolts
+}
+
+// InRange reports whether mv lies within the limits of the probe
+func (v VoltageProbe) InRange(mv Millivolts) bool {
+	return probeInRange(int16(mv), int16(v.MinimumValue), int16(v.MaximumValue))
 }
 
 func (v VoltageProbe) String() string {
 	return fmt.Sprintf("Voltage Probe\n"+
 		"\tDescription: %s\n"+
-		"\tLocation And Status: %s\n"+
-		"\tMaximum Value: %d\n"+
-		"\tMinimum Valude: %d\n"+
-		"\tResolution: %d\n"+
-		"\tTolerance: %d\n"+
-		"\tAccuracy: %d\n"+
-		"\tOE Mdefined: %d\n"+
-		"\tNominal Value: %d",
+		"\tLocation: %s\n"+
+		"\tStatus: %s\n"+
+		"\tMaximum Value: %s\n"+
+		"\tMinimum Value: %s\n"+
+		"\tResolution: %s\n"+
+		"\tTolerance: %s\n"+
+		"\tAccuracy: %s\n"+
+		"\tOEM-specific Information: 0x%08X\n"+
+		"\tNominal Value: %s",
 		v.Description,
-		v.LocationAndStatus,
+		v.LocationAndStatus.Location,
+		v.LocationAndStatus.Status,
 		v.MaximumValue,
-		v.MinimumValude,
+		v.MinimumValue,
 		v.Resolution,
 		v.Tolerance,
 		v.Accuracy,
 		v.OEMdefined,
 		v.NominalValue)
 }
+
+func newVoltageProbe(h dmiHeader) dmiTyper {
+	data := h.data
+	nominal := uint16(probeValueUnknown)
+	if h.Length > 0x14 {
+		nominal = u16(data[0x14:0x16])
+	}
+	return &VoltageProbe{
+		Description:       h.FieldString(int(data[0x04])),
+		LocationAndStatus: NewVoltageProbeLocationAndStatus(data[0x05]),
+		MaximumValue:      Millivolts(u16(data[0x06:0x08])),
+		MinimumValue:      Millivolts(u16(data[0x08:0x0A])),
+		Resolution:        VoltageProbeResolution(u16(data[0x0A:0x0C])),
+		Tolerance:         Millivolts(u16(data[0x0C:0x0E])),
+		Accuracy:          ProbeAccuracy(u16(data[0x0E:0x10])),
+		OEMdefined:        u32(data[0x10:0x14]),
+		NominalValue:      Millivolts(nominal),
+	}
+}
+
+func GetVoltageProbe() *VoltageProbe {
+	if d, ok := gdmi[SMBIOSStructureTypeVoltageProbe]; ok {
+		return d.(*VoltageProbe)
+	}
+	return nil
+}
+
+func GetVoltageProbes() []*VoltageProbe {
+	var vs []*VoltageProbe
+	for _, d := range GetStructures(SMBIOSStructureTypeVoltageProbe) {
+		vs = append(vs, d.(*VoltageProbe))
+	}
+	return vs
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeVoltageProbe, newVoltageProbe)
+}
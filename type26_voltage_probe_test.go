@@ -0,0 +1,28 @@
+package godmi
+
+import "testing"
+
+func TestProbeInRange(t *testing.T) {
+	const unknown = -0x8000
+	for _, tc := range []struct {
+		v, min, max int16
+		want        bool
+	}{
+		{1200, 1100, 1300, true},
+		{1100, 1100, 1300, true},
+		{1099, 1100, 1300, false},
+		{1301, 1100, 1300, false},
+		{-50, unknown, 1300, true},
+		{2000, 1100, unknown, true},
+		{1000, 1100, unknown, false},
+		{-300, unknown, unknown, true},
+	} {
+		if got := probeInRange(tc.v, tc.min, tc.max); got != tc.want {
+			t.Errorf("probeInRange(%d, %d, %d) = %t, want %t", tc.v, tc.min, tc.max, got, tc.want)
+		}
+	}
+	p := TemperatureProbe{MinimumValue: unknown, MaximumValue: 850}
+	if !p.InRange(-100) || p.InRange(851) {
+		t.Errorf("TemperatureProbe.InRange with limits %s, %s", p.MinimumValue, p.MaximumValue)
+	}
+}
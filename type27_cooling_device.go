@@ -8,29 +8,22 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
-type CoolingDeviceStatus byte
+type CoolingDeviceStatus ProbeStatus
 
 const (
-	CoolingDeviceStatusOther CoolingDeviceStatus = 0x20 + iota
-	CoolingDeviceStatusUnknown
-	CoolingDeviceStatusOK
-	CoolingDeviceStatusNon_critical
-	CoolingDeviceStatusCritical
-	CoolingDeviceStatusNon_recoverable
+	CoolingDeviceStatusOther           = CoolingDeviceStatus(ProbeStatusOther)
+	CoolingDeviceStatusUnknown         = CoolingDeviceStatus(ProbeStatusUnknown)
+	CoolingDeviceStatusOK              = CoolingDeviceStatus(ProbeStatusOK)
+	CoolingDeviceStatusNon_critical    = CoolingDeviceStatus(ProbeStatusNon_critical)
+	CoolingDeviceStatusCritical        = CoolingDeviceStatus(ProbeStatusCritical)
+	CoolingDeviceStatusNon_recoverable = CoolingDeviceStatus(ProbeStatusNon_recoverable)
 )
 
 func (c CoolingDeviceStatus) String() string {
-	status := [...]string{
-		"Other",
-		"Unknown",
-		"OK",
-		"Non-critical",
-		"Critical",
-		"Non-recoverable",
-	}
-	return status[c-0x20]
+	return ProbeStatus(c).String()
 }
 
 type CoolingDeviceType byte
@@ -63,7 +56,10 @@ func (c CoolingDeviceType) String() string {
 		"Active Cooling",
 		"Passive Cooling",
 	}
-	return types[c-1]
+	if c >= 1 && int(c) <= len(types) {
+		return types[c-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type CoolingDeviceTypeAndStatus struct {
@@ -73,39 +69,226 @@ type CoolingDeviceTypeAndStatus struct {
 
 func NewCoolingDeviceTypeAndStatus(data byte) CoolingDeviceTypeAndStatus {
 	return CoolingDeviceTypeAndStatus{
-		Status: CoolingDeviceStatus(data & 0xE0),
+		Status: CoolingDeviceStatus(data >> 5),
 		Type:   CoolingDeviceType(data & 0x1F),
 	}
 }
 
+func (c CoolingDeviceTypeAndStatus) String() string {
+	return fmt.Sprintf("\n\t\t\t\tStatus: %s\n\t\t\t\tType: %s",
+		c.Status, c.Type)
+}
+
+// RPM is a cooling device speed in revolutions per minute
+type RPM uint16
+
+func (r RPM) IsKnown() bool {
+	return r != probeValueUnknown
+}
+
+func (r RPM) String() string {
+	if !r.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d rpm", uint16(r))
+}
+
+// CoolingDeviceNoTemperatureProbe is the temperature probe handle of a
+// cooling device that is not driven by a probe
+const CoolingDeviceNoTemperatureProbe = 0xFFFF
+
 type CoolingDevice struct {
 	infoCommon
 	TemperatureProbeHandle uint16
 	DeviceTypeAndStatus    CoolingDeviceTypeAndStatus
 	CoolingUintGroup       byte
 	OEMdefined             uint32
-	NominalSpeed           uint16
+	NominalSpeed           RPM
 	Description            string
 }
 
 func (c CoolingDevice) String() string {
 	s := fmt.Sprintf("Cooling Device\n"+
-		"\tTemperature Probe Handle: %d\n"+
-		"\tDevice Type And Status: %s\n"+
-		"\tCooling Uint Group: %d\n"+
-		"\tOE Mdefined: %d\n",
+		"\tTemperature Probe Handle: 0x%04X\n"+
+		"\tType: %s\n"+
+		"\tStatus: %s\n"+
+		"\tCooling Unit Group: %d\n"+
+		"\tOEM-specific Information: 0x%08X",
 		c.TemperatureProbeHandle,
-		c.DeviceTypeAndStatus,
+		c.DeviceTypeAndStatus.Type,
+		c.DeviceTypeAndStatus.Status,
 		c.CoolingUintGroup,
 		c.OEMdefined,
 	)
 	if c.Length > 0x0C {
-		s += fmt.Sprintf("\tNominal Speed: %d\n", c.NominalSpeed)
+		s += fmt.Sprintf("\n\tNominal Speed: %s", c.NominalSpeed)
+	}
+	if c.Length > 0x0E {
+		s += fmt.Sprintf("\n\tDescription: %s", c.Description)
+	}
+	return s
+}
+
+func newCoolingDevice(h dmiHeader) dmiTyper {
+	data := h.data
+	cd := &CoolingDevice{
+		TemperatureProbeHandle: u16(data[0x04:0x06]),
+		DeviceTypeAndStatus:    NewCoolingDeviceTypeAndStatus(data[0x06]),
+		CoolingUintGroup:       data[0x07],
+		OEMdefined:             u32(data[0x08:0x0C]),
+		NominalSpeed:           probeValueUnknown,
+	}
+	if h.Length > 0x0C {
+		cd.NominalSpeed = RPM(u16(data[0x0C:0x0E]))
+	}
+	if h.Length > 0x0E {
+		cd.Description = h.FieldString(int(data[0x0E]))
+	}
+	return cd
+}
+
+func GetCoolingDevice() *CoolingDevice {
+	if d, ok := gdmi[SMBIOSStructureTypeCoolingDevice]; ok {
+		return d.(*CoolingDevice)
+	}
+	return nil
+}
+
+func GetCoolingDevices() []*CoolingDevice {
+	var cds []*CoolingDevice
+	for _, d := range GetStructures(SMBIOSStructureTypeCoolingDevice) {
+		cds = append(cds, d.(*CoolingDevice))
+	}
+	return cds
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeCoolingDevice, newCoolingDevice)
+}
+
+// CoolingGroup is a cooling unit group, or a single cooling device that is
+// not part of a group
+type CoolingGroup struct {
+	// Number is the cooling unit group, 0 for a device outside any group
+	Number  byte
+	Devices []*CoolingDevice
+	// Probes are the temperature probes the devices are driven by
+	Probes []*TemperatureProbe
+}
+
+// Redundant reports whether the group has more than one cooling device. By
+// the specification, devices sharing a cooling unit group are redundant.
+func (g CoolingGroup) Redundant() bool {
+	return g.Number != 0 && len(g.Devices) > 1
+}
+
+// NominalSpeed returns the sum of the known nominal speeds of the devices
+func (g CoolingGroup) NominalSpeed() int {
+	var n int
+	for _, d := range g.Devices {
+		if d.NominalSpeed.IsKnown() {
+			n += int(d.NominalSpeed)
+		}
+	}
+	return n
+}
+
+func coolingDeviceName(d *CoolingDevice) string {
+	if d.Description != "" {
+		return d.Description
+	}
+	return fmt.Sprintf("Cooling Device 0x%04X", d.Handle)
+}
+
+func (g CoolingGroup) String() string {
+	var s string
+	if g.Number == 0 {
+		s = "No Cooling Unit Group"
+	} else {
+		s = fmt.Sprintf("Cooling Unit Group %d: %d devices", g.Number, len(g.Devices))
+		if n := g.NominalSpeed(); n > 0 {
+			s += fmt.Sprintf(", %d rpm nominal", n)
+		}
+		if g.Redundant() {
+			s += ", redundant"
+		}
 	}
-	if c.Length > 0x0F {
-		s += fmt.Sprintf("\tDescription: %s\n", c.Description)
+	for _, d := range g.Devices {
+		s += fmt.Sprintf("\n\t%s: %s, %s, %s", coolingDeviceName(d),
+			d.DeviceTypeAndStatus.Type, d.NominalSpeed, d.DeviceTypeAndStatus.Status)
+	}
+	for _, p := range g.Probes {
+		s += fmt.Sprintf("\n\tDriven by %s (%s): %s", p.Description,
+			p.LocationAndStatus.Location, p.LocationAndStatus.Status)
 	}
 	return s
 }
 
+// ThermalTopology ties the cooling devices to their groups and to the
+// temperature probes that drive them
+type ThermalTopology struct {
+	Groups []CoolingGroup
+	// Unassigned are the temperature probes no cooling device refers to
+	Unassigned []*TemperatureProbe
+}
+
+func (t ThermalTopology) String() string {
+	s := "Thermal Topology"
+	for _, g := range t.Groups {
+		s += "\n\t" + strings.Replace(g.String(), "\n", "\n\t", -1)
+	}
+	if len(t.Unassigned) > 0 {
+		s += "\n\tTemperature Probes Without Cooling Device"
+		for _, p := range t.Unassigned {
+			s += fmt.Sprintf("\n\t\t%s (%s): %s", p.Description,
+				p.LocationAndStatus.Location, p.LocationAndStatus.Status)
+		}
+	}
+	return s
+}
 
+// NewThermalTopology groups the cooling devices by cooling unit group, in
+// table order, and resolves their temperature probe handles among ts.
+// Devices outside any group get a group of their own.
+func NewThermalTopology(cds []*CoolingDevice, ts []*TemperatureProbe) ThermalTopology {
+	var t ThermalTopology
+	probes := make(map[uint16]*TemperatureProbe)
+	for _, p := range ts {
+		probes[uint16(p.Handle)] = p
+	}
+	used := make(map[*TemperatureProbe]bool)
+	groups := make(map[byte]int)
+	for _, d := range cds {
+		i, ok := groups[d.CoolingUintGroup]
+		if !ok || d.CoolingUintGroup == 0 {
+			i = len(t.Groups)
+			t.Groups = append(t.Groups, CoolingGroup{Number: d.CoolingUintGroup})
+			groups[d.CoolingUintGroup] = i
+		}
+		g := &t.Groups[i]
+		g.Devices = append(g.Devices, d)
+		p, ok := probes[d.TemperatureProbeHandle]
+		if !ok || d.TemperatureProbeHandle == CoolingDeviceNoTemperatureProbe {
+			continue
+		}
+		dup := false
+		for _, gp := range g.Probes {
+			dup = dup || gp == p
+		}
+		if !dup {
+			g.Probes = append(g.Probes, p)
+		}
+		used[p] = true
+	}
+	for _, p := range ts {
+		if !used[p] {
+			t.Unassigned = append(t.Unassigned, p)
+		}
+	}
+	return t
+}
+
+// GetThermalTopology builds the thermal topology of the SMBIOS table
+func GetThermalTopology() ThermalTopology {
+	return NewThermalTopology(GetCoolingDevices(), GetTemperatureProbes())
+}
@@ -0,0 +1,71 @@
+package godmi
+
+import "testing"
+
+func TestNewThermalTopology(t *testing.T) {
+	cpu := &TemperatureProbe{infoCommon: infoCommon{Handle: 0x30}, Description: "CPU"}
+	inlet := &TemperatureProbe{infoCommon: infoCommon{Handle: 0x31}, Description: "Inlet"}
+	psu := &TemperatureProbe{infoCommon: infoCommon{Handle: 0x32}, Description: "PSU"}
+	fan := func(handle SMBIOSStructureHandle, group byte, probe uint16, speed RPM) *CoolingDevice {
+		return &CoolingDevice{
+			infoCommon:             infoCommon{Handle: handle},
+			TemperatureProbeHandle: probe,
+			CoolingUintGroup:       group,
+			NominalSpeed:           speed,
+		}
+	}
+	cds := []*CoolingDevice{
+		fan(0x20, 1, 0x30, 5000),
+		fan(0x21, 0, 0x31, 3000),
+		fan(0x22, 1, 0x30, probeValueUnknown),
+		fan(0x23, 0, CoolingDeviceNoTemperatureProbe, 3000),
+		// A dangling probe handle is ignored
+		fan(0x24, 2, 0x99, 4000),
+	}
+	topo := NewThermalTopology(cds, []*TemperatureProbe{cpu, inlet, psu})
+
+	want := []struct {
+		number  byte
+		devices []SMBIOSStructureHandle
+		probes  []*TemperatureProbe
+		speed   int
+		redund  bool
+	}{
+		{1, []SMBIOSStructureHandle{0x20, 0x22}, []*TemperatureProbe{cpu}, 5000, true},
+		{0, []SMBIOSStructureHandle{0x21}, []*TemperatureProbe{inlet}, 3000, false},
+		{0, []SMBIOSStructureHandle{0x23}, nil, 3000, false},
+		{2, []SMBIOSStructureHandle{0x24}, nil, 4000, false},
+	}
+	if len(topo.Groups) != len(want) {
+		t.Fatalf("NewThermalTopology: got %d groups, want %d\n%s", len(topo.Groups), len(want), topo)
+	}
+	for i, w := range want {
+		g := topo.Groups[i]
+		if g.Number != w.number || len(g.Devices) != len(w.devices) || len(g.Probes) != len(w.probes) ||
+			g.NominalSpeed() != w.speed || g.Redundant() != w.redund {
+			t.Errorf("group %d: got %s", i, g)
+			continue
+		}
+		for j, h := range w.devices {
+			if g.Devices[j].Handle != h {
+				t.Errorf("group %d device %d: got 0x%04X, want 0x%04X", i, j, g.Devices[j].Handle, h)
+			}
+		}
+		for j, p := range w.probes {
+			if g.Probes[j] != p {
+				t.Errorf("group %d probe %d: got %s, want %s", i, j, g.Probes[j].Description, p.Description)
+			}
+		}
+	}
+	if len(topo.Unassigned) != 1 || topo.Unassigned[0] != psu {
+		t.Errorf("Unassigned: got %v", topo.Unassigned)
+	}
+}
+
+func TestNewThermalTopologyEmpty(t *testing.T) {
+	probe := &TemperatureProbe{infoCommon: infoCommon{Handle: 0x30}}
+	topo := NewThermalTopology(nil, []*TemperatureProbe{probe})
+	if len(topo.Groups) != 0 || len(topo.Unassigned) != 1 {
+		t.Errorf("NewThermalTopology without cooling devices: got %+v", topo)
+	}
+}
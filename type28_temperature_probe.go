@@ -10,33 +10,25 @@ import (
 	"fmt"
 )
 
-type TemperatureProbeStatus byte
+type TemperatureProbeStatus ProbeStatus
 
 const (
-	TemperatureProbeStatusOther TemperatureProbeStatus = 0x20 + iota
-	TemperatureProbeStatusUnknown
-	TemperatureProbeStatusOK
-	TemperatureProbeStatusNon_critical
-	TemperatureProbeStatusCritical
-	TemperatureProbeStatusNon_recoverable
+	TemperatureProbeStatusOther           = TemperatureProbeStatus(ProbeStatusOther)
+	TemperatureProbeStatusUnknown         = TemperatureProbeStatus(ProbeStatusUnknown)
+	TemperatureProbeStatusOK              = TemperatureProbeStatus(ProbeStatusOK)
+	TemperatureProbeStatusNon_critical    = TemperatureProbeStatus(ProbeStatusNon_critical)
+	TemperatureProbeStatusCritical        = TemperatureProbeStatus(ProbeStatusCritical)
+	TemperatureProbeStatusNon_recoverable = TemperatureProbeStatus(ProbeStatusNon_recoverable)
 )
 
 func (t TemperatureProbeStatus) String() string {
-	status := [...]string{
-		"Other",
-		"Unknown",
-		"OK",
-		"Non-critical",
-		"Critical",
-		"Non-recoverable",
-	}
-	return status[t-0x20]
+	return ProbeStatus(t).String()
 }
 
 type TemperatureProbeLocation byte
 
 const (
-	TemperatureProbeLocationOther TemperatureProbeStatus = 1 + iota
+	TemperatureProbeLocationOther TemperatureProbeLocation = 1 + iota
 	TemperatureProbeLocationUnknown
 	TemperatureProbeLocationProcessor
 	TemperatureProbeLocationDisk
@@ -71,7 +63,10 @@ func (t TemperatureProbeLocation) String() string {
 		"Power System Board",
 		"Drive Back Plane",
 	}
-	return locations[t-1]
+	if t >= 1 && int(t) <= len(locations) {
+		return locations[t-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type TemperatureProbeLocationAndStatus struct {
@@ -79,44 +74,85 @@ type TemperatureProbeLocationAndStatus struct {
 	Location TemperatureProbeLocation
 }
 
-func (t TemperatureProbeLocationAndStatus) String() string {
-	return fmt.Sprintf("\n\t\t\t\tStatus: %s\n\t\t\t\tLocation: %s",
-		t.Status, t.Location)
-}
-
 func NewTemperatureProbeLocationAndStatus(data byte) TemperatureProbeLocationAndStatus {
 	return TemperatureProbeLocationAndStatus{
-		Status:   TemperatureProbeStatus(data & 0xE0),
+		Status:   TemperatureProbeStatus(data >> 5),
 		Location: TemperatureProbeLocation(data & 0x1F),
 	}
 }
 
+// Decicelsius is a temperature probe value in 1/10 degree Celsius
+type Decicelsius int16
+
+func (d Decicelsius) IsKnown() bool {
+	return uint16(d) != probeValueUnknown
+}
+
+// Celsius returns the value in degree Celsius
+func (d Decicelsius) Celsius() float64 {
+	return float64(d) / 10
+}
+
+func (d Decicelsius) String() string {
+	if !d.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.1f deg C", d.Celsius())
+}
+
+// TemperatureProbeResolution is the resolution of a temperature probe in
+// 1/1000 degree Celsius
+type TemperatureProbeResolution uint16
+
+func (t TemperatureProbeResolution) IsKnown() bool {
+	return t != probeValueUnknown
+}
+
+// Celsius returns the resolution in degree Celsius
+func (t TemperatureProbeResolution) Celsius() float64 {
+	return float64(t) / 1000
+}
+
+func (t TemperatureProbeResolution) String() string {
+	if !t.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.3f deg C", t.Celsius())
+}
+
 type TemperatureProbe struct {
 	infoCommon
 	Description       string
 	LocationAndStatus TemperatureProbeLocationAndStatus
-	MaximumValue      uint16
-	MinimumValue      uint16
-	Resolution        uint16
-	Tolerance         uint16
-	Accuracy          uint16
+	MaximumValue      Decicelsius
+	MinimumValue      Decicelsius
+	Resolution        TemperatureProbeResolution
+	Tolerance         Decicelsius
+	Accuracy          ProbeAccuracy
 	OEMdefined        uint32
-	NominalValue      uint16
+	NominalValue      Decicelsius
+}
+
+// InRange reports whether d lies within the limits of the probe
+func (t TemperatureProbe) InRange(d Decicelsius) bool {
+	return probeInRange(int16(d), int16(t.MinimumValue), int16(t.MaximumValue))
 }
 
 func (t TemperatureProbe) String() string {
 	return fmt.Sprintf("Temperature Probe\n"+
 		"\tDescription: %s\n"+
-		"\tLocation And Status: %s\n"+
-		"\tMaximum Value: %d\n"+
-		"\tMinimum Value: %d\n"+
-		"\tResolution: %d\n"+
-		"\tTolerance: %d\n"+
-		"\tAccuracy: %d\n"+
-		"\tOE Mdefined: %d\n"+
-		"\tNominal Value: %d",
+		"\tLocation: %s\n"+
+		"\tStatus: %s\n"+
+		"\tMaximum Value: %s\n"+
+		"\tMinimum Value: %s\n"+
+		"\tResolution: %s\n"+
+		"\tTolerance: %s\n"+
+		"\tAccuracy: %s\n"+
+		"\tOEM-specific Information: 0x%08X\n"+
+		"\tNominal Value: %s",
 		t.Description,
-		t.LocationAndStatus,
+		t.LocationAndStatus.Location,
+		t.LocationAndStatus.Status,
 		t.MaximumValue,
 		t.MinimumValue,
 		t.Resolution,
@@ -125,3 +161,41 @@ func (t TemperatureProbe) String() string {
 		t.OEMdefined,
 		t.NominalValue)
 }
+
+func newTemperatureProbe(h dmiHeader) dmiTyper {
+	data := h.data
+	nominal := uint16(probeValueUnknown)
+	if h.Length > 0x14 {
+		nominal = u16(data[0x14:0x16])
+	}
+	return &TemperatureProbe{
+		Description:       h.FieldString(int(data[0x04])),
+		LocationAndStatus: NewTemperatureProbeLocationAndStatus(data[0x05]),
+		MaximumValue:      Decicelsius(u16(data[0x06:0x08])),
+		MinimumValue:      Decicelsius(u16(data[0x08:0x0A])),
+		Resolution:        TemperatureProbeResolution(u16(data[0x0A:0x0C])),
+		Tolerance:         Decicelsius(u16(data[0x0C:0x0E])),
+		Accuracy:          ProbeAccuracy(u16(data[0x0E:0x10])),
+		OEMdefined:        u32(data[0x10:0x14]),
+		NominalValue:      Decicelsius(nominal),
+	}
+}
+
+func GetTemperatureProbe() *TemperatureProbe {
+	if d, ok := gdmi[SMBIOSStructureTypeTemperatureProbe]; ok {
+		return d.(*TemperatureProbe)
+	}
+	return nil
+}
+
+func GetTemperatureProbes() []*TemperatureProbe {
+	var ts []*TemperatureProbe
+	for _, d := range GetStructures(SMBIOSStructureTypeTemperatureProbe) {
+		ts = append(ts, d.(*TemperatureProbe))
+	}
+	return ts
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeTemperatureProbe, newTemperatureProbe)
+}
@@ -10,27 +10,19 @@ import (
 	"fmt"
 )
 
-type ElectricalCurrentProbeStatus byte
+type ElectricalCurrentProbeStatus ProbeStatus
 
 const (
-	ElectricalCurrentProbeStatusOther ElectricalCurrentProbeStatus = 0x20 + iota
-	ElectricalCurrentProbeStatusUnknown
-	ElectricalCurrentProbeStatusOK
-	ElectricalCurrentProbeStatusNon_critical
-	ElectricalCurrentProbeStatusCritical
-	ElectricalCurrentProbeStatusNon_recoverable
+	ElectricalCurrentProbeStatusOther           = ElectricalCurrentProbeStatus(ProbeStatusOther)
+	ElectricalCurrentProbeStatusUnknown         = ElectricalCurrentProbeStatus(ProbeStatusUnknown)
+	ElectricalCurrentProbeStatusOK              = ElectricalCurrentProbeStatus(ProbeStatusOK)
+	ElectricalCurrentProbeStatusNon_critical    = ElectricalCurrentProbeStatus(ProbeStatusNon_critical)
+	ElectricalCurrentProbeStatusCritical        = ElectricalCurrentProbeStatus(ProbeStatusCritical)
+	ElectricalCurrentProbeStatusNon_recoverable = ElectricalCurrentProbeStatus(ProbeStatusNon_recoverable)
 )
 
 func (e ElectricalCurrentProbeStatus) String() string {
-	status := [...]string{
-		"Other",
-		"Unknown",
-		"OK",
-		"Non-critical",
-		"Critical",
-		"Non-recoverable",
-	}
-	return status[e-0x20]
+	return ProbeStatus(e).String()
 }
 
 type ElectricalCurrentProbeLocation byte
@@ -63,7 +55,10 @@ func (e ElectricalCurrentProbeLocation) String() string {
 		"Power Unit",
 		"Add-in Card",
 	}
-	return locations[e-1]
+	if e >= 1 && int(e) <= len(locations) {
+		return locations[e-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type ElectricalCurrentProbeLocationAndStatus struct {
@@ -71,51 +66,128 @@ type ElectricalCurrentProbeLocationAndStatus struct {
 	Location ElectricalCurrentProbeLocation
 }
 
-func (e ElectricalCurrentProbeLocationAndStatus) String() string {
-	return fmt.Sprintf("\n\t\t\t\tStatus: %s\n\t\t\t\tLocation: %s",
-		e.Status, e.Location)
-
-}
-
 func NewElectricalCurrentProbeLocationAndStatus(data byte) ElectricalCurrentProbeLocationAndStatus {
 	return ElectricalCurrentProbeLocationAndStatus{
-		Status:   ElectricalCurrentProbeStatus(data & 0xE0),
+		Status:   ElectricalCurrentProbeStatus(data >> 5),
 		Location: ElectricalCurrentProbeLocation(data & 0x1F),
 	}
 }
 
+// Milliamps is an electrical current probe value in mA
+type Milliamps int16
+
+func (m Milliamps) IsKnown() bool {
+	return uint16(m) != probeValueUnknown
+}
+
+// Amps returns the value in A
+func (m Milliamps) Amps() float64 {
+	return float64(m) / 1000
+}
+
+func (m Milliamps) String() string {
+	if !m.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.3f A", m.Amps())
+}
+
+// ElectricalCurrentProbeResolution is the resolution of an electrical
+// current probe in 1/10 mA
+type ElectricalCurrentProbeResolution uint16
+
+func (e ElectricalCurrentProbeResolution) IsKnown() bool {
+	return e != probeValueUnknown
+}
+
+// Milliamps returns the resolution in mA
+func (e ElectricalCurrentProbeResolution) Milliamps() float64 {
+	return float64(e) / 10
+}
+
+func (e ElectricalCurrentProbeResolution) String() string {
+	if !e.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%.1f mA", e.Milliamps())
+}
+
 type ElectricalCurrentProbe struct {
 	infoCommon
 	Description       string
 	LocationAndStatus ElectricalCurrentProbeLocationAndStatus
-	MaximumValue      uint16
-	MinimumValue      uint16
-	Resolution        uint16
-	Tolerance         uint16
-	Accuracy          uint16
+	MaximumValue      Milliamps
+	MinimumValue      Milliamps
+	Resolution        ElectricalCurrentProbeResolution
+	Tolerance         Milliamps
+	Accuracy          ProbeAccuracy
 	OEMdefined        uint32
-	NomimalValue      uint16
+	NominalValue      Milliamps
+}
+
+// InRange reports whether m lies within the limits of the probe
+func (e ElectricalCurrentProbe) InRange(m Milliamps) bool {
+	return probeInRange(int16(m), int16(e.MinimumValue), int16(e.MaximumValue))
 }
 
 func (e ElectricalCurrentProbe) String() string {
 	return fmt.Sprintf("Electrical Current Probe\n"+
 		"\tDescription: %s\n"+
-		"\tLocation And Status: %s\n"+
-		"\tMaximum Value: %d\n"+
-		"\tMinimum Value: %d\n"+
-		"\tResolution: %d\n"+
-		"\tTolerance: %d\n"+
-		"\tAccuracy: %d\n"+
-		"\tOE Mdefined: %d\n"+
-		"\tNomimal Value: %d\n",
+		"\tLocation: %s\n"+
+		"\tStatus: %s\n"+
+		"\tMaximum Value: %s\n"+
+		"\tMinimum Value: %s\n"+
+		"\tResolution: %s\n"+
+		"\tTolerance: %s\n"+
+		"\tAccuracy: %s\n"+
+		"\tOEM-specific Information: 0x%08X\n"+
+		"\tNominal Value: %s",
 		e.Description,
-		e.LocationAndStatus,
+		e.LocationAndStatus.Location,
+		e.LocationAndStatus.Status,
 		e.MaximumValue,
 		e.MinimumValue,
 		e.Resolution,
 		e.Tolerance,
 		e.Accuracy,
 		e.OEMdefined,
-		e.NomimalValue)
+		e.NominalValue)
+}
+
+func newElectricalCurrentProbe(h dmiHeader) dmiTyper {
+	data := h.data
+	nominal := uint16(probeValueUnknown)
+	if h.Length > 0x14 {
+		nominal = u16(data[0x14:0x16])
+	}
+	return &ElectricalCurrentProbe{
+		Description:       h.FieldString(int(data[0x04])),
+		LocationAndStatus: NewElectricalCurrentProbeLocationAndStatus(data[0x05]),
+		MaximumValue:      Milliamps(u16(data[0x06:0x08])),
+		MinimumValue:      Milliamps(u16(data[0x08:0x0A])),
+		Resolution:        ElectricalCurrentProbeResolution(u16(data[0x0A:0x0C])),
+		Tolerance:         Milliamps(u16(data[0x0C:0x0E])),
+		Accuracy:          ProbeAccuracy(u16(data[0x0E:0x10])),
+		OEMdefined:        u32(data[0x10:0x14]),
+		NominalValue:      Milliamps(nominal),
+	}
+}
+
+func GetElectricalCurrentProbe() *ElectricalCurrentProbe {
+	if d, ok := gdmi[SMBIOSStructureTypeElectricalCurrentProbe]; ok {
+		return d.(*ElectricalCurrentProbe)
+	}
+	return nil
 }
 
+func GetElectricalCurrentProbes() []*ElectricalCurrentProbe {
+	var es []*ElectricalCurrentProbe
+	for _, d := range GetStructures(SMBIOSStructureTypeElectricalCurrentProbe) {
+		es = append(es, d.(*ElectricalCurrentProbe))
+	}
+	return es
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeElectricalCurrentProbe, newElectricalCurrentProbe)
+}
@@ -80,6 +80,16 @@ func (b BaseboardType) String() string {
 	return "Out Of Spec"
 }
 
+type BaseboardContainedObjectHandles []uint16
+
+func (b BaseboardContainedObjectHandles) String() string {
+	var s string
+	for _, h := range b {
+		s += fmt.Sprintf("\n\t\t0x%04X", h)
+	}
+	return s
+}
+
 type BaseboardInformation struct {
 	infoCommon
 	Manufacturer                   string
@@ -92,7 +102,7 @@ type BaseboardInformation struct {
 	ChassisHandle                  uint16
 	BoardType                      BaseboardType
 	NumberOfContainedObjectHandles byte
-	ContainedObjectHandles         []byte
+	ContainedObjectHandles         BaseboardContainedObjectHandles
 }
 
 func (b BaseboardInformation) String() string {
@@ -104,7 +114,9 @@ func (b BaseboardInformation) String() string {
 		"\tAsset Tag: %s\n"+
 		"\tFeatures:%s\n"+
 		"\tLocation In Chassis: %s\n"+
-		"\tType: %s",
+		"\tChassis Handle: 0x%04X\n"+
+		"\tType: %s\n"+
+		"\tContained Object Handles: %d%s",
 		b.Manufacturer,
 		b.ProductName,
 		b.Version,
@@ -112,12 +124,15 @@ func (b BaseboardInformation) String() string {
 		b.AssetTag,
 		b.FeatureFlags,
 		b.LocationInChassis,
-		b.BoardType)
+		b.ChassisHandle,
+		b.BoardType,
+		b.NumberOfContainedObjectHandles,
+		b.ContainedObjectHandles)
 }
 
 func newBaseboardInformation(h dmiHeader) dmiTyper {
 	data := h.data
-	return &BaseboardInformation{
+	bi := &BaseboardInformation{
 		Manufacturer:      h.FieldString(int(data[0x04])),
 		ProductName:       h.FieldString(int(data[0x05])),
 		Version:           h.FieldString(int(data[0x06])),
@@ -125,8 +140,20 @@ func newBaseboardInformation(h dmiHeader) dmiTyper {
 		AssetTag:          h.FieldString(int(data[0x08])),
 		FeatureFlags:      BaseboardFeatureFlags(data[0x09]),
 		LocationInChassis: h.FieldString(int(data[0x0A])),
+		ChassisHandle:     u16(data[0x0B:0x0D]),
 		BoardType:         BaseboardType(data[0x0D]),
 	}
+	if h.Length > 0x0E {
+		bi.NumberOfContainedObjectHandles = data[0x0E]
+	}
+	for i := 0; i < int(bi.NumberOfContainedObjectHandles); i++ {
+		offset := 0x0F + 2*i
+		if offset+2 > int(h.Length) {
+			break
+		}
+		bi.ContainedObjectHandles = append(bi.ContainedObjectHandles, u16(data[offset:offset+2]))
+	}
+	return bi
 }
 
 func GetBaseboardInformation() *BaseboardInformation {
@@ -136,6 +163,109 @@ func GetBaseboardInformation() *BaseboardInformation {
 	return nil
 }
 
+func GetBaseboardInformations() []*BaseboardInformation {
+	var bis []*BaseboardInformation
+	for _, d := range GetStructures(SMBIOSStructureTypeBaseBoard) {
+		bis = append(bis, d.(*BaseboardInformation))
+	}
+	return bis
+}
+
+// BaseboardTopology is a board together with the chassis it is located in
+// and the structures it contains
+type BaseboardTopology struct {
+	Board    *BaseboardInformation
+	Chassis  *ChassisInformation
+	Contents []interface{}
+}
+
+func newBaseboardTopology(b *BaseboardInformation) BaseboardTopology {
+	t := BaseboardTopology{Board: b}
+	if c, ok := GetStructure(SMBIOSStructureHandle(b.ChassisHandle)).(*ChassisInformation); ok {
+		t.Chassis = c
+	}
+	for _, h := range b.ContainedObjectHandles {
+		if d := GetStructure(SMBIOSStructureHandle(h)); d != nil {
+			t.Contents = append(t.Contents, d)
+		}
+	}
+	return t
+}
+
+// Processors returns the processors contained in the board
+func (t BaseboardTopology) Processors() []*ProcessorInformation {
+	var ps []*ProcessorInformation
+	for _, d := range t.Contents {
+		if p, ok := d.(*ProcessorInformation); ok {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// SystemSlots returns the system slots contained in the board
+func (t BaseboardTopology) SystemSlots() []*SystemSlot {
+	var ss []*SystemSlot
+	for _, d := range t.Contents {
+		if s, ok := d.(*SystemSlot); ok {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// MemoryDevices returns the memory devices contained in the board, either
+// directly or through a contained physical memory array
+func (t BaseboardTopology) MemoryDevices() []*MemoryDevice {
+	var mds []*MemoryDevice
+	arrays := make(map[uint16]bool)
+	for _, d := range t.Contents {
+		switch v := d.(type) {
+		case *MemoryDevice:
+			mds = append(mds, v)
+		case *PhysicalMemoryArray:
+			arrays[uint16(v.Handle)] = true
+		}
+	}
+	if len(arrays) == 0 {
+		return mds
+	}
+	for _, d := range GetStructures(SMBIOSStructureTypeMemoryDevice) {
+		md := d.(*MemoryDevice)
+		if arrays[md.PhysicalMemoryArrayHandle] {
+			mds = append(mds, md)
+		}
+	}
+	return mds
+}
+
+func (t BaseboardTopology) String() string {
+	s := fmt.Sprintf("Base Board %s %s (0x%04X)",
+		t.Board.Manufacturer,
+		t.Board.ProductName,
+		t.Board.Handle)
+	if t.Chassis != nil {
+		s += fmt.Sprintf("\n\tChassis: %s %s (0x%04X)",
+			t.Chassis.Manufacturer,
+			t.Chassis.Type,
+			t.Chassis.Handle)
+	}
+	for _, d := range t.Contents {
+		ic := d.(infoCommoner).common()
+		s += fmt.Sprintf("\n\t%s (0x%04X)", ic.SMType, ic.Handle)
+	}
+	return s
+}
+
+// GetBaseboardTopology returns the contents of every base board in the table
+func GetBaseboardTopology() []BaseboardTopology {
+	var ts []BaseboardTopology
+	for _, b := range GetBaseboardInformations() {
+		ts = append(ts, newBaseboardTopology(b))
+	}
+	return ts
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeBaseBoard, newBaseboardInformation)
 }
@@ -0,0 +1,90 @@
+package godmi
+
+import "testing"
+
+func baseboard(handle, chassis uint16, count byte, objects ...uint16) []byte {
+	b := make([]byte, 0x0B+2*len(objects))
+	put16(b, 0x07, chassis)
+	b[0x09] = byte(BaseboardTypeMotherboard)
+	b[0x0A] = count
+	for i, o := range objects {
+		put16(b, 0x0B+2*i, o)
+	}
+	return structure(2, handle, b)
+}
+
+func memoryDevice(handle, array uint16, locator string) []byte {
+	b := make([]byte, 0x11)
+	put16(b, 0x00, array)
+	b[0x0C] = 1
+	return structure(17, handle, b, locator)
+}
+
+func TestNewBaseboardInformation(t *testing.T) {
+	loadTable(
+		baseboard(0x0200, 0x0300, 2, 0x0400, 0x0900),
+		// The count claims more handles than the structure holds
+		baseboard(0x0201, 0x0300, 3, 0x0400, 0x0900),
+		// An SMBIOS 2.0 board ends before the count
+		structure(2, 0x0202, make([]byte, 0x0A)))
+	bs := GetBaseboardInformations()
+	if len(bs) != 3 {
+		t.Fatalf("GetBaseboardInformations: got %d", len(bs))
+	}
+	for i, want := range []BaseboardContainedObjectHandles{{0x0400, 0x0900}, {0x0400, 0x0900}, nil} {
+		b := bs[i]
+		if len(b.ContainedObjectHandles) != len(want) {
+			t.Errorf("board %d: got handles %v, want %v", i, b.ContainedObjectHandles, want)
+			continue
+		}
+		for j, h := range want {
+			if b.ContainedObjectHandles[j] != h {
+				t.Errorf("board %d handle %d: got 0x%04X, want 0x%04X", i, j, b.ContainedObjectHandles[j], h)
+			}
+		}
+	}
+	if bs[0].ChassisHandle != 0x0300 || bs[1].NumberOfContainedObjectHandles != 3 || bs[2].NumberOfContainedObjectHandles != 0 {
+		t.Errorf("GetBaseboardInformations: got %v", bs)
+	}
+}
+
+func TestGetBaseboardTopology(t *testing.T) {
+	loadTable(
+		baseboard(0x0200, 0x0300, 5, 0x0400, 0x0900, 0x1000, 0x1100, 0x9999),
+		// The chassis handle of this board names a processor
+		baseboard(0x0201, 0x0400, 0),
+		structure(3, 0x0300, make([]byte, 0x12)),
+		structure(4, 0x0400, make([]byte, 0x26)),
+		structure(9, 0x0900, []byte{1, 0, 0, 0, 0, 0, 0, 0}, "PCIE1"),
+		structure(16, 0x1000, make([]byte, 0x13)),
+		structure(16, 0x1001, make([]byte, 0x13)),
+		memoryDevice(0x1100, 0x1001, "DIMM_A1"),
+		memoryDevice(0x1101, 0x1000, "DIMM_B1"),
+		memoryDevice(0x1102, 0x1001, "DIMM_C1"))
+	ts := GetBaseboardTopology()
+	if len(ts) != 2 {
+		t.Fatalf("GetBaseboardTopology: got %v", ts)
+	}
+	board, other := ts[0], ts[1]
+	if board.Chassis == nil || board.Chassis.Handle != 0x0300 {
+		t.Errorf("Chassis: got %v", board.Chassis)
+	}
+	// The dangling handle is dropped
+	if len(board.Contents) != 4 {
+		t.Errorf("Contents: got %v", board)
+	}
+	if ps := board.Processors(); len(ps) != 1 || ps[0].Handle != 0x0400 {
+		t.Errorf("Processors: got %v", ps)
+	}
+	if ss := board.SystemSlots(); len(ss) != 1 || ss[0].Designation != "PCIE1" {
+		t.Errorf("SystemSlots: got %v", ss)
+	}
+	// The contained device first, then the devices of the contained array
+	mds := board.MemoryDevices()
+	if len(mds) != 2 || mds[0].DeviceLocator != "DIMM_A1" || mds[1].DeviceLocator != "DIMM_B1" {
+		t.Errorf("MemoryDevices: got %v", mds)
+	}
+	if other.Chassis != nil || len(other.Contents) != 0 || len(other.MemoryDevices()) != 0 {
+		t.Errorf("board without contents: got %v", other)
+	}
+}
@@ -34,10 +34,37 @@ type OutOfBandRemoteAccess struct {
 }
 
 func (o OutOfBandRemoteAccess) String() string {
-	return fmt.Sprintf("Out Of Band Remote Access:\n\t\t"+
-		"Manufacturer Name: %s\n\t\t"+
-		"Connections: %s\n",
+	return fmt.Sprintf("Out Of Band Remote Access\n"+
+		"\tManufacturer Name: %s\n"+
+		"\tConnections: %s",
 		o.ManufacturerName,
 		o.Connections)
 }
 
+func newOutOfBandRemoteAccess(h dmiHeader) dmiTyper {
+	data := h.data
+	return &OutOfBandRemoteAccess{
+		ManufacturerName: h.FieldString(int(data[0x04])),
+		Connections:      NewOutOfBandRemoteAccessConnections(data[0x05]),
+	}
+}
+
+func GetOutOfBandRemoteAccess() *OutOfBandRemoteAccess {
+	if d, ok := gdmi[SMBIOSStructureTypeOut_of_bandRemoteAccess]; ok {
+		return d.(*OutOfBandRemoteAccess)
+	}
+	return nil
+}
+
+func GetOutOfBandRemoteAccesses() []*OutOfBandRemoteAccess {
+	var rs []*OutOfBandRemoteAccess
+	for _, d := range GetStructures(SMBIOSStructureTypeOut_of_bandRemoteAccess) {
+		rs = append(rs, d.(*OutOfBandRemoteAccess))
+	}
+	return rs
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeOut_of_bandRemoteAccess, newOutOfBandRemoteAccess)
+}
+
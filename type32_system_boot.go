@@ -12,6 +12,30 @@ import (
 
 type SystemBootInformationStatus byte
 
+const (
+	SystemBootInformationStatusNoErrors SystemBootInformationStatus = iota
+	SystemBootInformationStatusNoBootableMedia
+	SystemBootInformationStatusOSFailedToLoad
+	SystemBootInformationStatusFirmwareDetectedHardwareFailure
+	SystemBootInformationStatusOSDetectedHardwareFailure
+	SystemBootInformationStatusUserRequestedBoot
+	SystemBootInformationStatusSecurityViolation
+	SystemBootInformationStatusPreviouslyRequestedImage
+	SystemBootInformationStatusWatchdogExpired
+)
+
+// IsVendorSpecific reports whether the status is in the vendor/OEM-specific
+// range 128-191
+func (s SystemBootInformationStatus) IsVendorSpecific() bool {
+	return s >= 128 && s <= 191
+}
+
+// IsProductSpecific reports whether the status is in the product-specific
+// range 192-255
+func (s SystemBootInformationStatus) IsProductSpecific() bool {
+	return s >= 192
+}
+
 func (s SystemBootInformationStatus) String() string {
 	status := [...]string{
 		"No errors detected", /* 0 */
@@ -24,25 +48,79 @@ func (s SystemBootInformationStatus) String() string {
 		"Previously-requested image",
 		"System watchdog timer expired",
 	}
-	if s <= 8 {
+	if int(s) < len(status) {
 		return status[s]
-	} else if s >= 128 && s <= 191 {
+	} else if s.IsVendorSpecific() {
 		return "OEM-specific"
-	} else if s > 192 && s <= 255 {
+	} else if s.IsProductSpecific() {
 		return "Product-specific"
 	}
-	return "Error"
+	return OUT_OF_SPEC
 }
 
 type SystemBootInformation struct {
 	infoCommon
-	BootStatus SystemBootInformationStatus
+	// StatusPresent is false when the structure ends before the status, in
+	// which case BootStatus is not a report of the firmware
+	StatusPresent bool
+	BootStatus    SystemBootInformationStatus
+	// AdditionalData is the status specific data following the status byte
+	AdditionalData []byte
+}
+
+// HardwareFailure reports whether the last boot found a hardware failure,
+// either in firmware or in the operating system
+func (s SystemBootInformation) HardwareFailure() bool {
+	return s.BootStatus == SystemBootInformationStatusFirmwareDetectedHardwareFailure ||
+		s.BootStatus == SystemBootInformationStatusOSDetectedHardwareFailure
+}
+
+// Failed reports whether the last boot reported an error. Vendor and product
+// specific statuses are not known to be errors and do not count.
+func (s SystemBootInformation) Failed() bool {
+	switch s.BootStatus {
+	case SystemBootInformationStatusNoBootableMedia,
+		SystemBootInformationStatusOSFailedToLoad,
+		SystemBootInformationStatusFirmwareDetectedHardwareFailure,
+		SystemBootInformationStatusOSDetectedHardwareFailure,
+		SystemBootInformationStatusSecurityViolation,
+		SystemBootInformationStatusWatchdogExpired:
+		return true
+	}
+	return false
 }
 
 func (s SystemBootInformation) String() string {
-	return fmt.Sprintf("System Boot Information\n"+
-		"\tBoot Status: %s",
+	if !s.StatusPresent {
+		return "System Boot Information\n\tStatus: Not Reported"
+	}
+	str := fmt.Sprintf("System Boot Information\n"+
+		"\tStatus: %s",
 		s.BootStatus)
+	if len(s.AdditionalData) > 0 {
+		str += fmt.Sprintf("\n\tAdditional Data: % X", s.AdditionalData)
+	}
+	return str
 }
 
+func newSystemBootInformation(h dmiHeader) dmiTyper {
+	data := h.data
+	si := &SystemBootInformation{}
+	if int(h.Length) > 0x0A {
+		si.StatusPresent = true
+		si.BootStatus = SystemBootInformationStatus(data[0x0A])
+		si.AdditionalData = append([]byte(nil), data[0x0B:h.Length]...)
+	}
+	return si
+}
 
+func GetSystemBootInformation() *SystemBootInformation {
+	if d, ok := gdmi[SMBIOSStructureTypeSystemBoot]; ok {
+		return d.(*SystemBootInformation)
+	}
+	return nil
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeSystemBoot, newSystemBootInformation)
+}
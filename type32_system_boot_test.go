@@ -0,0 +1,48 @@
+package godmi
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSystemBootInformation(t *testing.T) {
+	reserved := make([]byte, 6)
+	for _, tc := range []struct {
+		name    string
+		body    []byte
+		present bool
+		status  SystemBootInformationStatus
+		data    string
+		failed  bool
+	}{
+		{"no status", reserved, false, SystemBootInformationStatusNoErrors, "", false},
+		{"no errors", append(reserved, 0), true, SystemBootInformationStatusNoErrors, "", false},
+		{"watchdog", append(reserved, 8), true, SystemBootInformationStatusWatchdogExpired, "", true},
+		{"hardware failure", append(reserved, 3, 0x12, 0x34), true,
+			SystemBootInformationStatusFirmwareDetectedHardwareFailure, "12 34", true},
+		{"vendor specific", append(reserved, 130), true, 130, "", false},
+	} {
+		loadTable(structure(32, 0x20, tc.body, "boot"))
+		s := GetSystemBootInformation()
+		if s == nil || s.StatusPresent != tc.present || s.BootStatus != tc.status ||
+			fmt.Sprintf("% x", s.AdditionalData) != tc.data || s.Failed() != tc.failed {
+			t.Errorf("%s: got %+v", tc.name, s)
+		}
+	}
+}
+
+func TestSystemBootInformationNoStatus(t *testing.T) {
+	loadTable(structure(32, 0x20, make([]byte, 6)))
+	if got := GetSystemBootInformation().String(); got != "System Boot Information\n\tStatus: Not Reported" {
+		t.Errorf("String: got %q", got)
+	}
+}
+
+func TestSystemBootInformationCopiesData(t *testing.T) {
+	b := structure(32, 0x20, []byte{0, 0, 0, 0, 0, 0, 3, 0x12, 0x34})
+	s := newSystemBootInformation(*newdmiHeader(b)).(*SystemBootInformation)
+	b[0x0B] = 0xFF
+	if s.AdditionalData[0] != 0x12 {
+		t.Errorf("AdditionalData: got % x", s.AdditionalData)
+	}
+}
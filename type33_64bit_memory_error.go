@@ -6,37 +6,37 @@
 */
 package godmi
 
-import (
-	"fmt"
-)
-
 type _64BitMemoryErrorInformation struct {
 	infoCommon
-	Type              MemoryErrorInformationType
-	Granularity       MemoryErrorInformationGranularity
-	Operation         MemoryErrorInformationOperation
-	VendorSyndrome    uint32
-	ArrayErrorAddress uint32
-	ErrorAddress      uint32
-	Reslution         uint32
+	MemoryErrorInformation
 }
 
 func (m _64BitMemoryErrorInformation) String() string {
-	return fmt.Sprintf("_64 Bit Memory Error Information\n"+
-		"\tType: %s\n"+
-		"\tGranularity: %s\n"+
-		"\tOperation: %s\n"+
-		"\tVendor Syndrome: %d\n"+
-		"\tArray Error Address: %d\n"+
-		"\tError Address: %d\n"+
-		"\tReslution: %d",
-		m.Type,
-		m.Granularity,
-		m.Operation,
-		m.VendorSyndrome,
-		m.ArrayErrorAddress,
-		m.ErrorAddress,
-		m.Reslution)
+	return "64-bit Memory Error Information\n" + m.MemoryErrorInformation.String()
 }
 
+func new_64BitMemoryErrorInformation(h dmiHeader) dmiTyper {
+	data := h.data
+	return &_64BitMemoryErrorInformation{
+		MemoryErrorInformation: MemoryErrorInformation{
+			Type:              MemoryErrorInformationType(data[0x04]),
+			Granularity:       MemoryErrorInformationGranularity(data[0x05]),
+			Operation:         MemoryErrorInformationOperation(data[0x06]),
+			VendorSyndrome:    u32(data[0x07:0x0B]),
+			ArrayErrorAddress: MemoryErrorAddress(u64(data[0x0B:0x13])),
+			ErrorAddress:      MemoryErrorAddress(u64(data[0x13:0x1B])),
+			Resolution:        MemoryErrorResolution(u32(data[0x1B:0x1F])),
+		},
+	}
+}
 
+func Get_64BitMemoryErrorInformation() *_64BitMemoryErrorInformation {
+	if d, ok := gdmi[SMBIOSStructureType64_bitMemoryError]; ok {
+		return d.(*_64BitMemoryErrorInformation)
+	}
+	return nil
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureType64_bitMemoryError, new_64BitMemoryErrorInformation)
+}
@@ -0,0 +1,40 @@
+package godmi
+
+import "testing"
+
+func memoryError64(handle uint16, typ MemoryErrorInformationType, array, device uint64) []byte {
+	b := make([]byte, 0x1B)
+	b[0x00] = byte(typ)
+	b[0x01] = byte(MemoryErrorInformationGranularityMemorypartitionlevel)
+	b[0x02] = byte(MemoryErrorInformationOperationPartialwrite)
+	put16(b, 0x03, 0xCAFE)
+	for i := uint(0); i < 8; i++ {
+		b[0x07+i] = byte(array >> (8 * i))
+		b[0x0F+i] = byte(device >> (8 * i))
+	}
+	put16(b, 0x17, 64)
+	return structure(33, handle, b)
+}
+
+func TestNew64BitMemoryErrorInformation(t *testing.T) {
+	loadTable(memoryError64(0x2100, MemoryErrorInformationTypeUncorrectableerror, 0x8000000000000000, 0x123456789A))
+	m := Get_64BitMemoryErrorInformation()
+	if m == nil {
+		t.Fatal("Get_64BitMemoryErrorInformation: got nil")
+	}
+	if m.Type != MemoryErrorInformationTypeUncorrectableerror || !m.Type.IsError() ||
+		m.Granularity != MemoryErrorInformationGranularityMemorypartitionlevel ||
+		m.Operation != MemoryErrorInformationOperationPartialwrite || m.VendorSyndrome != 0xCAFE {
+		t.Errorf("got %v", m)
+	}
+	// The addresses are read at 0x0B and 0x13, eight bytes each
+	if m.ArrayErrorAddress != MemoryErrorAddressUnknown || m.ArrayErrorAddress.String() != "Unknown" {
+		t.Errorf("ArrayErrorAddress: got %s", m.ArrayErrorAddress)
+	}
+	if m.ErrorAddress != 0x123456789A || m.ErrorAddress.String() != "0x123456789A" {
+		t.Errorf("ErrorAddress: got %s", m.ErrorAddress)
+	}
+	if m.Resolution != 64 || m.Resolution.String() != "64 bytes" {
+		t.Errorf("Resolution: got %s", m.Resolution)
+	}
+}
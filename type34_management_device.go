@@ -8,6 +8,7 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type ManagementDeviceType byte
@@ -44,7 +45,10 @@ func (m ManagementDeviceType) String() string {
 		"Winbond W83781D",
 		"Holtek HT82H791",
 	}
-	return types[m-1]
+	if m >= 1 && int(m) <= len(types) {
+		return types[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type ManagementDeviceAddressType byte
@@ -65,7 +69,10 @@ func (m ManagementDeviceAddressType) String() string {
 		"Memory",
 		"SM Bus",
 	}
-	return types[m-1]
+	if m >= 1 && int(m) <= len(types) {
+		return types[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type ManagementDevice struct {
@@ -80,7 +87,7 @@ func (m ManagementDevice) String() string {
 	return fmt.Sprintf("Management Device\n"+
 		"\tDescription: %s\n"+
 		"\tType: %s\n"+
-		"\tAddress: %d\n"+
+		"\tAddress: 0x%08X\n"+
 		"\tAddress Type: %s",
 		m.Description,
 		m.Type,
@@ -88,3 +95,117 @@ func (m ManagementDevice) String() string {
 		m.AddressType)
 }
 
+func newManagementDevice(h dmiHeader) dmiTyper {
+	data := h.data
+	return &ManagementDevice{
+		Description: h.FieldString(int(data[0x04])),
+		Type:        ManagementDeviceType(data[0x05]),
+		Address:     u32(data[0x06:0x0A]),
+		AddressType: ManagementDeviceAddressType(data[0x0A]),
+	}
+}
+
+func GetManagementDevice() *ManagementDevice {
+	if d, ok := gdmi[SMBIOSStructureTypeManagementDevice]; ok {
+		return d.(*ManagementDevice)
+	}
+	return nil
+}
+
+func GetManagementDevices() []*ManagementDevice {
+	var ms []*ManagementDevice
+	for _, d := range GetStructures(SMBIOSStructureTypeManagementDevice) {
+		ms = append(ms, d.(*ManagementDevice))
+	}
+	return ms
+}
+
+// ManagementDeviceMonitor is a component monitored by a management device,
+// with the probe or cooling device it is and its thresholds
+type ManagementDeviceMonitor struct {
+	Component *ManagementDeviceComponent
+	// Monitored is the *VoltageProbe, *CoolingDevice, *TemperatureProbe or
+	// *ElectricalCurrentProbe, or nil if the handle does not resolve
+	Monitored interface{}
+	// Thresholds is nil when the component has none
+	Thresholds *ManagementDeviceThresholdData
+}
+
+// Evaluate classifies a reading of the monitored component against its
+// thresholds. Without thresholds the reading cannot be checked and its
+// status is unknown.
+func (m ManagementDeviceMonitor) Evaluate(reading int) ProbeStatus {
+	if m.Thresholds == nil {
+		return ProbeStatusUnknown
+	}
+	return m.Thresholds.Evaluate(reading)
+}
+
+func monitoredName(i interface{}) string {
+	switch m := i.(type) {
+	case *VoltageProbe:
+		return fmt.Sprintf("Voltage Probe %s", m.Description)
+	case *CoolingDevice:
+		return coolingDeviceName(m)
+	case *TemperatureProbe:
+		return fmt.Sprintf("Temperature Probe %s", m.Description)
+	case *ElectricalCurrentProbe:
+		return fmt.Sprintf("Electrical Current Probe %s", m.Description)
+	}
+	return "Unknown"
+}
+
+func (m ManagementDeviceMonitor) String() string {
+	s := fmt.Sprintf("%s: %s", m.Component.Description, monitoredName(m.Monitored))
+	if m.Thresholds != nil {
+		s += "\n\t" + m.Thresholds.thresholds()
+	}
+	return s
+}
+
+// ManagementDeviceTree is a management device with the components it
+// monitors
+type ManagementDeviceTree struct {
+	Device   *ManagementDevice
+	Monitors []ManagementDeviceMonitor
+}
+
+func (m ManagementDeviceTree) String() string {
+	s := fmt.Sprintf("%s (%s at 0x%08X, %s)", m.Device.Description, m.Device.Type,
+		m.Device.Address, m.Device.AddressType)
+	for _, mon := range m.Monitors {
+		s += "\n\t" + strings.Replace(mon.String(), "\n", "\n\t", -1)
+	}
+	return s
+}
+
+// NewManagementDeviceTrees attaches the components to their management
+// devices and resolves what they monitor and their thresholds
+func NewManagementDeviceTrees(devs []*ManagementDevice, comps []*ManagementDeviceComponent) []ManagementDeviceTree {
+	var ts []ManagementDeviceTree
+	for _, d := range devs {
+		t := ManagementDeviceTree{Device: d}
+		for _, c := range comps {
+			if c.ManagementDeviceHandle != uint16(d.Handle) {
+				continue
+			}
+			t.Monitors = append(t.Monitors, ManagementDeviceMonitor{
+				Component:  c,
+				Monitored:  c.Component(),
+				Thresholds: c.Thresholds(),
+			})
+		}
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+// GetManagementDeviceTrees builds the management device trees of the SMBIOS
+// table
+func GetManagementDeviceTrees() []ManagementDeviceTree {
+	return NewManagementDeviceTrees(GetManagementDevices(), GetManagementDeviceComponents())
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeManagementDevice, newManagementDevice)
+}
@@ -0,0 +1,67 @@
+package godmi
+
+import "testing"
+
+func managementDevice(handle uint16, typ ManagementDeviceType, desc string) []byte {
+	return structure(34, handle, []byte{1, byte(typ), 0x2C, 0, 0, 0, byte(ManagementDeviceAddressTypeSMBus)}, desc)
+}
+
+func managementDeviceComponent(handle, device, component, threshold uint16, desc string) []byte {
+	b := make([]byte, 7)
+	b[0] = 1
+	put16(b, 1, device)
+	put16(b, 3, component)
+	put16(b, 5, threshold)
+	return structure(35, handle, b, desc)
+}
+
+func voltageProbe(handle uint16, desc string) []byte {
+	b := make([]byte, 0x12)
+	b[0] = 1
+	b[1] = 0x63
+	for _, off := range []int{0x02, 0x04, 0x06, 0x08, 0x0A, 0x10} {
+		put16(b, off, probeValueUnknown)
+	}
+	return structure(26, handle, b, desc)
+}
+
+func TestNewManagementDeviceTrees(t *testing.T) {
+	th := make([]byte, 12)
+	for i, v := range []uint16{1100, 1300, 1000, 1400, 900, 1500} {
+		put16(th, 2*i, v)
+	}
+	loadTable(
+		managementDevice(0x3400, ManagementDeviceTypeNationalSemiconductorLM78, "LM78-1"),
+		managementDevice(0x3401, ManagementDeviceTypeNationalSemiconductorLM75, "LM75-1"),
+		voltageProbe(0x2600, "VCore"),
+		structure(36, 0x3600, th),
+		managementDeviceComponent(0x3500, 0x3400, 0x2600, 0x3600, "CPU Core"),
+		// Dangling component and threshold handles resolve to nil
+		managementDeviceComponent(0x3501, 0x3400, 0x9999, 0x9999, "Missing"),
+		managementDeviceComponent(0x3502, 0x3400, 0x2600, ManagementDeviceComponentNoThreshold, "No Threshold"),
+		// A component of no listed device is left out
+		managementDeviceComponent(0x3503, 0x9999, 0x2600, 0x3600, "Orphan"))
+
+	if n := len(GetManagementDeviceThresholdData()); n != 1 {
+		t.Fatalf("GetManagementDeviceThresholdData: got %d", n)
+	}
+	ts := GetManagementDeviceTrees()
+	if len(ts) != 2 || ts[0].Device.Description != "LM78-1" || len(ts[0].Monitors) != 3 ||
+		ts[1].Device.Description != "LM75-1" || len(ts[1].Monitors) != 0 {
+		t.Fatalf("GetManagementDeviceTrees: got %v", ts)
+	}
+	core, missing, none := ts[0].Monitors[0], ts[0].Monitors[1], ts[0].Monitors[2]
+	if vp, ok := core.Monitored.(*VoltageProbe); !ok || vp.Description != "VCore" {
+		t.Errorf("CPU Core monitors %v", core.Monitored)
+	}
+	if core.Thresholds == nil || core.Thresholds.UpperThresholdCritical != 1400 ||
+		core.Evaluate(1450) != ProbeStatusCritical || core.Evaluate(1200) != ProbeStatusOK {
+		t.Errorf("CPU Core thresholds: got %v", core.Thresholds)
+	}
+	if missing.Monitored != nil || missing.Thresholds != nil || missing.Evaluate(2000) != ProbeStatusUnknown {
+		t.Errorf("Missing: got %v", missing)
+	}
+	if none.Monitored == nil || none.Thresholds != nil || none.Evaluate(1200) != ProbeStatusUnknown {
+		t.Errorf("No Threshold: got %v", none)
+	}
+}
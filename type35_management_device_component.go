@@ -10,6 +10,10 @@ import (
 	"fmt"
 )
 
+// ManagementDeviceComponentNoThreshold is the threshold handle of a
+// component without threshold data
+const ManagementDeviceComponentNoThreshold = 0xFFFF
+
 type ManagementDeviceComponent struct {
 	infoCommon
 	Description            string
@@ -19,13 +23,74 @@ type ManagementDeviceComponent struct {
 }
 
 func (m ManagementDeviceComponent) String() string {
+	threshold := "None"
+	if m.ThresholdHandle != ManagementDeviceComponentNoThreshold {
+		threshold = fmt.Sprintf("0x%04X", m.ThresholdHandle)
+	}
 	return fmt.Sprintf("Management Device Component\n"+
 		"\tDescription: %s\n"+
-		"\tManagement Device Handle: %d\n"+
-		"\tComponent Handle: %d\n"+
-		"\tThreshold Handle: %d",
+		"\tManagement Device Handle: 0x%04X\n"+
+		"\tComponent Handle: 0x%04X\n"+
+		"\tThreshold Handle: %s",
 		m.Description,
 		m.ManagementDeviceHandle,
 		m.ComponentHandle,
-		m.ThresholdHandle)
+		threshold)
+}
+
+// ManagementDevice returns the management device of the component, or nil
+func (m ManagementDeviceComponent) ManagementDevice() *ManagementDevice {
+	d, _ := GetStructure(SMBIOSStructureHandle(m.ManagementDeviceHandle)).(*ManagementDevice)
+	return d
+}
+
+// Component returns the probe or cooling device the component is
+func (m ManagementDeviceComponent) Component() interface{} {
+	switch c := GetStructure(SMBIOSStructureHandle(m.ComponentHandle)).(type) {
+	case *VoltageProbe, *CoolingDevice, *TemperatureProbe, *ElectricalCurrentProbe:
+		return c
+	}
+	return nil
+}
+
+// Thresholds returns the threshold data of the component, or nil
+func (m ManagementDeviceComponent) Thresholds() *ManagementDeviceThresholdData {
+	if m.ThresholdHandle == ManagementDeviceComponentNoThreshold {
+		return nil
+	}
+	t, _ := GetStructure(SMBIOSStructureHandle(m.ThresholdHandle)).(*ManagementDeviceThresholdData)
+	return t
+}
+
+func newManagementDeviceComponent(h dmiHeader) dmiTyper {
+	data := h.data
+	m := &ManagementDeviceComponent{
+		Description:            h.FieldString(int(data[0x04])),
+		ManagementDeviceHandle: u16(data[0x05:0x07]),
+		ComponentHandle:        u16(data[0x07:0x09]),
+		ThresholdHandle:        ManagementDeviceComponentNoThreshold,
+	}
+	if h.Length >= 0x0B {
+		m.ThresholdHandle = u16(data[0x09:0x0B])
+	}
+	return m
+}
+
+func GetManagementDeviceComponent() *ManagementDeviceComponent {
+	if d, ok := gdmi[SMBIOSStructureTypeManagementDeviceComponent]; ok {
+		return d.(*ManagementDeviceComponent)
+	}
+	return nil
+}
+
+func GetManagementDeviceComponents() []*ManagementDeviceComponent {
+	var ms []*ManagementDeviceComponent
+	for _, d := range GetStructures(SMBIOSStructureTypeManagementDeviceComponent) {
+		ms = append(ms, d.(*ManagementDeviceComponent))
+	}
+	return ms
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeManagementDeviceComponent, newManagementDeviceComponent)
 }
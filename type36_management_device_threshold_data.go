@@ -10,24 +10,39 @@ import (
 	"fmt"
 )
 
+// ManagementDeviceThreshold is a threshold in the units of the monitored
+// component, such as mV for a voltage probe
+type ManagementDeviceThreshold int16
+
+func (m ManagementDeviceThreshold) IsKnown() bool {
+	return uint16(m) != probeValueUnknown
+}
+
+func (m ManagementDeviceThreshold) String() string {
+	if !m.IsKnown() {
+		return "Not Available"
+	}
+	return fmt.Sprintf("%d", int16(m))
+}
+
 type ManagementDeviceThresholdData struct {
 	infoCommon
-	LowerThresholdNonCritical    uint16
-	UpperThresholdNonCritical    uint16
-	LowerThresholdCritical       uint16
-	UpperThresholdCritical       uint16
-	LowerThresholdNonRecoverable uint16
-	UpperThresholdNonRecoverable uint16
+	LowerThresholdNonCritical    ManagementDeviceThreshold
+	UpperThresholdNonCritical    ManagementDeviceThreshold
+	LowerThresholdCritical       ManagementDeviceThreshold
+	UpperThresholdCritical       ManagementDeviceThreshold
+	LowerThresholdNonRecoverable ManagementDeviceThreshold
+	UpperThresholdNonRecoverable ManagementDeviceThreshold
 }
 
 func (m ManagementDeviceThresholdData) String() string {
 	return fmt.Sprintf("Management Device Threshold Data\n"+
-		"\tLower Threshold Non Critical: %d\n"+
-		"\tUpper Threshold Non Critical: %d\n"+
-		"\tLower Threshold Critical: %d\n"+
-		"\tUpper Threshold Critical: %d\n"+
-		"\tLower Threshold Non Recoverable: %d\n"+
-		"\tUpper Threshold Non Recoverable: %d",
+		"\tLower Non-critical Threshold: %s\n"+
+		"\tUpper Non-critical Threshold: %s\n"+
+		"\tLower Critical Threshold: %s\n"+
+		"\tUpper Critical Threshold: %s\n"+
+		"\tLower Non-recoverable Threshold: %s\n"+
+		"\tUpper Non-recoverable Threshold: %s",
 		m.LowerThresholdNonCritical,
 		m.UpperThresholdNonCritical,
 		m.LowerThresholdCritical,
@@ -35,3 +50,61 @@ func (m ManagementDeviceThresholdData) String() string {
 		m.LowerThresholdNonRecoverable,
 		m.UpperThresholdNonRecoverable)
 }
+
+// thresholds prints the thresholds on one line, from lower to upper
+func (m ManagementDeviceThresholdData) thresholds() string {
+	return fmt.Sprintf("Thresholds: %s / %s / %s .. %s / %s / %s",
+		m.LowerThresholdNonRecoverable,
+		m.LowerThresholdCritical,
+		m.LowerThresholdNonCritical,
+		m.UpperThresholdNonCritical,
+		m.UpperThresholdCritical,
+		m.UpperThresholdNonRecoverable)
+}
+
+// Evaluate classifies a reading, in the units of the monitored component,
+// against the thresholds. As with IPMI sensors, a reading at a threshold
+// has crossed it. Thresholds that are not available are skipped.
+func (m ManagementDeviceThresholdData) Evaluate(reading int) ProbeStatus {
+	levels := []struct {
+		lower, upper ManagementDeviceThreshold
+		status       ProbeStatus
+	}{
+		{m.LowerThresholdNonRecoverable, m.UpperThresholdNonRecoverable, ProbeStatusNon_recoverable},
+		{m.LowerThresholdCritical, m.UpperThresholdCritical, ProbeStatusCritical},
+		{m.LowerThresholdNonCritical, m.UpperThresholdNonCritical, ProbeStatusNon_critical},
+	}
+	for _, l := range levels {
+		if l.lower.IsKnown() && reading <= int(l.lower) {
+			return l.status
+		}
+		if l.upper.IsKnown() && reading >= int(l.upper) {
+			return l.status
+		}
+	}
+	return ProbeStatusOK
+}
+
+func newManagementDeviceThresholdData(h dmiHeader) dmiTyper {
+	data := h.data
+	return &ManagementDeviceThresholdData{
+		LowerThresholdNonCritical:    ManagementDeviceThreshold(u16(data[0x04:0x06])),
+		UpperThresholdNonCritical:    ManagementDeviceThreshold(u16(data[0x06:0x08])),
+		LowerThresholdCritical:       ManagementDeviceThreshold(u16(data[0x08:0x0A])),
+		UpperThresholdCritical:       ManagementDeviceThreshold(u16(data[0x0A:0x0C])),
+		LowerThresholdNonRecoverable: ManagementDeviceThreshold(u16(data[0x0C:0x0E])),
+		UpperThresholdNonRecoverable: ManagementDeviceThreshold(u16(data[0x0E:0x10])),
+	}
+}
+
+func GetManagementDeviceThresholdData() []*ManagementDeviceThresholdData {
+	var ms []*ManagementDeviceThresholdData
+	for _, d := range GetStructures(SMBIOSStructureTypeManagementDeviceThresholdData) {
+		ms = append(ms, d.(*ManagementDeviceThresholdData))
+	}
+	return ms
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeManagementDeviceThresholdData, newManagementDeviceThresholdData)
+}
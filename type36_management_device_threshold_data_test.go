@@ -0,0 +1,48 @@
+package godmi
+
+import "testing"
+
+func TestManagementDeviceThresholdDataEvaluate(t *testing.T) {
+	const na = -0x8000
+	m := ManagementDeviceThresholdData{
+		LowerThresholdNonRecoverable: 900,
+		LowerThresholdCritical:       1000,
+		LowerThresholdNonCritical:    1100,
+		UpperThresholdNonCritical:    1300,
+		UpperThresholdCritical:       1400,
+		UpperThresholdNonRecoverable: 1500,
+	}
+	partial := ManagementDeviceThresholdData{
+		LowerThresholdNonRecoverable: na,
+		LowerThresholdCritical:       na,
+		LowerThresholdNonCritical:    na,
+		UpperThresholdNonCritical:    na,
+		UpperThresholdCritical:       850,
+		UpperThresholdNonRecoverable: na,
+	}
+	for _, tc := range []struct {
+		m       ManagementDeviceThresholdData
+		reading int
+		want    ProbeStatus
+	}{
+		{m, 1200, ProbeStatusOK},
+		{m, 1101, ProbeStatusOK},
+		{m, 1299, ProbeStatusOK},
+		// A reading at a threshold has crossed it
+		{m, 1100, ProbeStatusNon_critical},
+		{m, 1300, ProbeStatusNon_critical},
+		{m, 1000, ProbeStatusCritical},
+		{m, 1450, ProbeStatusCritical},
+		{m, 900, ProbeStatusNon_recoverable},
+		{m, 2000, ProbeStatusNon_recoverable},
+		{m, -40000, ProbeStatusNon_recoverable},
+		{partial, -40000, ProbeStatusOK},
+		{partial, 849, ProbeStatusOK},
+		{partial, 850, ProbeStatusCritical},
+		{partial, 40000, ProbeStatusCritical},
+	} {
+		if got := tc.m.Evaluate(tc.reading); got != tc.want {
+			t.Errorf("Evaluate(%d) with %s: got %s, want %s", tc.reading, tc.m.thresholds(), got, tc.want)
+		}
+	}
+}
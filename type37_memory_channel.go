@@ -8,6 +8,7 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type MemoryChannelType byte
@@ -26,7 +27,10 @@ func (m MemoryChannelType) String() string {
 		"RamBus",
 		"SyncLink",
 	}
-	return types[m-1]
+	if m >= 1 && int(m) <= len(types) {
+		return types[m-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type MemoryDeviceLoadHandle struct {
@@ -38,14 +42,14 @@ type MemoryDeviceLoadHandles []MemoryDeviceLoadHandle
 
 func newMemoryDeviceLoadHandles(data []byte, count byte, length byte) MemoryDeviceLoadHandles {
 	md := make([]MemoryDeviceLoadHandle, 0)
-	if length < 0x07+count {
-		return md
-	}
-	for i := byte(1); i <= count; i++ {
+	for i := 0; i < int(count); i++ {
+		offset := 0x07 + 3*i
+		if offset+3 > int(length) {
+			break
+		}
 		var mem MemoryDeviceLoadHandle
-		offset := 3 * (i - 1)
-		mem.Load = data[0x07+offset]
-		mem.Handle = u16(data[0x08+offset : 0x0A+offset])
+		mem.Load = data[offset]
+		mem.Handle = u16(data[offset+1 : offset+3])
 		md = append(md, mem)
 	}
 	return md
@@ -54,7 +58,7 @@ func newMemoryDeviceLoadHandles(data []byte, count byte, length byte) MemoryDevi
 func (m MemoryDeviceLoadHandles) String() string {
 	var s string
 	for _, md := range m {
-		s += fmt.Sprintf("\n\t\tDevice: %d\tHandle %d", md.Load, md.Handle)
+		s += fmt.Sprintf("\n\t\tDevice: 0x%04X (Load %d)", md.Handle, md.Load)
 	}
 	return s
 }
@@ -69,11 +73,355 @@ type MemoryChannel struct {
 
 func (m MemoryChannel) String() string {
 	return fmt.Sprintf("Memory Channel\n"+
-		"\tChannel Type: %s\n"+
-		"\tMaximum Channel Load: %d\n"+
+		"\tType: %s\n"+
+		"\tMaximal Load: %d\n"+
+		"\tDevices: %d"+
 		"%s",
 		m.ChannelType,
 		m.MaximumChannelLoad,
+		m.MemoryDeviceCount,
 		m.LoadHandle)
 }
 
+// MemoryChannelDevice is a memory device on a channel and the load it puts
+// on the channel
+type MemoryChannelDevice struct {
+	Load byte
+	// Device is nil when the handle does not refer to a memory device
+	Device *MemoryDevice
+	Handle uint16
+}
+
+func (m MemoryChannelDevice) String() string {
+	if m.Device == nil {
+		return fmt.Sprintf("0x%04X (Load %d): not a memory device", m.Handle, m.Load)
+	}
+	return fmt.Sprintf("%s (Load %d)", deviceName(m.Device), m.Load)
+}
+
+// Devices resolves the load handles of the channel to memory devices
+func (m MemoryChannel) Devices() []MemoryChannelDevice {
+	var ds []MemoryChannelDevice
+	for _, lh := range m.LoadHandle {
+		d := MemoryChannelDevice{Load: lh.Load, Handle: lh.Handle}
+		d.Device, _ = GetStructure(SMBIOSStructureHandle(lh.Handle)).(*MemoryDevice)
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// Load returns the sum of the loads of the devices on the channel
+func (m MemoryChannel) Load() int {
+	var n int
+	for _, lh := range m.LoadHandle {
+		n += int(lh.Load)
+	}
+	return n
+}
+
+// Validate checks the channel against its own limits: every declared device
+// is listed, every handle refers to a memory device, and the devices do not
+// load the channel beyond its maximum load.
+func (m MemoryChannel) Validate() error {
+	if int(m.MemoryDeviceCount) != len(m.LoadHandle) {
+		return fmt.Errorf("memory channel 0x%04X: %d devices declared, %d listed",
+			m.Handle, m.MemoryDeviceCount, len(m.LoadHandle))
+	}
+	for _, d := range m.Devices() {
+		if d.Device == nil {
+			return fmt.Errorf("memory channel 0x%04X: handle 0x%04X is not a memory device", m.Handle, d.Handle)
+		}
+	}
+	if load := m.Load(); load > int(m.MaximumChannelLoad) {
+		return fmt.Errorf("memory channel 0x%04X: load %d exceeds maximum %d",
+			m.Handle, load, m.MaximumChannelLoad)
+	}
+	return nil
+}
+
+func newMemoryChannel(h dmiHeader) dmiTyper {
+	data := h.data
+	mc := &MemoryChannel{
+		ChannelType:        MemoryChannelType(data[0x04]),
+		MaximumChannelLoad: data[0x05],
+		MemoryDeviceCount:  data[0x06],
+	}
+	mc.LoadHandle = newMemoryDeviceLoadHandles(data, data[0x06], h.Length)
+	return mc
+}
+
+func GetMemoryChannel() *MemoryChannel {
+	if d, ok := gdmi[SMBIOSStructureTypeMemoryChannel]; ok {
+		return d.(*MemoryChannel)
+	}
+	return nil
+}
+
+func GetMemoryChannels() []*MemoryChannel {
+	var mcs []*MemoryChannel
+	for _, d := range GetStructures(SMBIOSStructureTypeMemoryChannel) {
+		mcs = append(mcs, d.(*MemoryChannel))
+	}
+	return mcs
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeMemoryChannel, newMemoryChannel)
+}
+
+// MemoryChannelPopulation is a memory channel with the memory devices on it
+type MemoryChannelPopulation struct {
+	Channel *MemoryChannel
+	// Slots are all the memory devices of the channel, Populated only the
+	// installed ones
+	Slots     []*MemoryDevice
+	Populated []*MemoryDevice
+}
+
+// Capacity returns the installed memory of the channel in bytes
+func (c MemoryChannelPopulation) Capacity() uint64 {
+	var n uint64
+	for _, md := range c.Populated {
+		n += md.SizeBytes()
+	}
+	return n
+}
+
+func (c MemoryChannelPopulation) String() string {
+	return fmt.Sprintf("Memory Channel 0x%04X: %d/%d populated, %s",
+		c.Channel.Handle, len(c.Populated), len(c.Slots), formatBytes(c.Capacity()))
+}
+
+// MemoryArrayPopulation is a physical memory array with its memory devices
+type MemoryArrayPopulation struct {
+	Array     *PhysicalMemoryArray
+	Slots     []*MemoryDevice
+	Populated []*MemoryDevice
+	Channels  []MemoryChannelPopulation
+}
+
+func (a MemoryArrayPopulation) String() string {
+	s := fmt.Sprintf("Physical Memory Array 0x%04X: %d/%d slots populated",
+		a.Array.Handle, len(a.Populated), a.Array.NumberOfMemoryDevices)
+	for _, c := range a.Channels {
+		s += "\n\t" + c.String()
+	}
+	return s
+}
+
+type MemoryPopulationIssueKind byte
+
+const (
+	MemoryPopulationIssueMixedSizes MemoryPopulationIssueKind = 1 + iota
+	MemoryPopulationIssueMixedSpeeds
+	MemoryPopulationIssueMixedRanks
+	MemoryPopulationIssueMixedPartNumbers
+	MemoryPopulationIssueUnbalancedChannels
+	MemoryPopulationIssueSlowDevice
+	MemoryPopulationIssueSlotCountMismatch
+)
+
+func (k MemoryPopulationIssueKind) String() string {
+	kinds := [...]string{
+		"Mixed sizes",
+		"Mixed speeds",
+		"Mixed ranks",
+		"Mixed part numbers",
+		"Unbalanced channels",
+		"Configured speed below rated speed",
+		"Slot count mismatch",
+	}
+	if k >= 1 && int(k) <= len(kinds) {
+		return kinds[k-1]
+	}
+	return OUT_OF_SPEC
+}
+
+// MemoryPopulationIssue is a finding of AnalyzeMemoryPopulation
+type MemoryPopulationIssue struct {
+	Kind    MemoryPopulationIssueKind
+	Array   *PhysicalMemoryArray
+	Devices []*MemoryDevice
+	Detail  string
+}
+
+func (i MemoryPopulationIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Detail)
+}
+
+// MemoryPopulation is the result of AnalyzeMemoryPopulation
+type MemoryPopulation struct {
+	Arrays []MemoryArrayPopulation
+	Issues []MemoryPopulationIssue
+}
+
+func (m MemoryPopulation) String() string {
+	var s string
+	for _, a := range m.Arrays {
+		s += a.String() + "\n"
+	}
+	if len(m.Issues) == 0 {
+		return s + "No issues"
+	}
+	for _, i := range m.Issues {
+		s += i.String() + "\n"
+	}
+	return strings.TrimSuffix(s, "\n")
+}
+
+func formatBytes(n uint64) string {
+	switch {
+	case n >= 1<<30 && n%(1<<30) == 0:
+		return fmt.Sprintf("%d GB", n>>30)
+	case n >= 1<<20:
+		return fmt.Sprintf("%d MB", n>>20)
+	}
+	return fmt.Sprintf("%d kB", n>>10)
+}
+
+func deviceName(md *MemoryDevice) string {
+	return strings.TrimSpace(md.BankLocator + " " + md.DeviceLocator)
+}
+
+// distinct groups the devices by the value key returns, skipping the
+// devices with an empty key. It returns the keys in order of appearance.
+func distinct(mds []*MemoryDevice, key func(*MemoryDevice) string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, md := range mds {
+		k := key(md)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// AnalyzeMemoryPopulation reports how the memory arrays are populated and
+// flags the rules a memory upgrade should keep: the same size, speed, rank
+// and part number for every DIMM of an array, the same number and size of
+// DIMMs on every channel, and DIMMs running at their rated speed. A value
+// a device leaves unknown, or that its structure predates, is not checked.
+//
+// Channels come from the memory channel structures (type 37); firmware that
+// does not provide them gets no channel balance check.
+func AnalyzeMemoryPopulation(arrays []*PhysicalMemoryArray, mds []*MemoryDevice, channels []*MemoryChannel) MemoryPopulation {
+	var mp MemoryPopulation
+	byHandle := make(map[uint16]*MemoryDevice)
+	for _, md := range mds {
+		byHandle[uint16(md.Handle)] = md
+	}
+	for _, a := range arrays {
+		ap := MemoryArrayPopulation{Array: a}
+		for _, md := range mds {
+			if md.PhysicalMemoryArrayHandle != uint16(a.Handle) {
+				continue
+			}
+			ap.Slots = append(ap.Slots, md)
+			if md.Installed() {
+				ap.Populated = append(ap.Populated, md)
+			}
+		}
+		for _, c := range channels {
+			cp := MemoryChannelPopulation{Channel: c}
+			for _, lh := range c.LoadHandle {
+				md, ok := byHandle[lh.Handle]
+				if !ok || md.PhysicalMemoryArrayHandle != uint16(a.Handle) {
+					continue
+				}
+				cp.Slots = append(cp.Slots, md)
+				if md.Installed() {
+					cp.Populated = append(cp.Populated, md)
+				}
+			}
+			if len(cp.Slots) > 0 {
+				ap.Channels = append(ap.Channels, cp)
+			}
+		}
+		mp.Arrays = append(mp.Arrays, ap)
+		mp.Issues = append(mp.Issues, ap.issues()...)
+	}
+	return mp
+}
+
+func (a MemoryArrayPopulation) issues() []MemoryPopulationIssue {
+	var is []MemoryPopulationIssue
+	add := func(k MemoryPopulationIssueKind, mds []*MemoryDevice, format string, args ...interface{}) {
+		is = append(is, MemoryPopulationIssue{
+			Kind:    k,
+			Array:   a.Array,
+			Devices: mds,
+			Detail:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if int(a.Array.NumberOfMemoryDevices) != len(a.Slots) {
+		add(MemoryPopulationIssueSlotCountMismatch, a.Slots,
+			"array 0x%04X declares %d slots, %d found", a.Array.Handle, a.Array.NumberOfMemoryDevices, len(a.Slots))
+	}
+
+	checks := []struct {
+		kind MemoryPopulationIssueKind
+		key  func(*MemoryDevice) string
+	}{
+		{MemoryPopulationIssueMixedSizes, func(md *MemoryDevice) string {
+			if md.SizeBytes() == 0 {
+				return ""
+			}
+			return formatBytes(md.SizeBytes())
+		}},
+		{MemoryPopulationIssueMixedSpeeds, func(md *MemoryDevice) string {
+			if md.Speed == 0 || md.Speed == 0xFFFF {
+				return ""
+			}
+			return fmt.Sprintf("%d MT/s", md.Speed)
+		}},
+		{MemoryPopulationIssueMixedRanks, func(md *MemoryDevice) string {
+			if md.Rank() == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%d rank", md.Rank())
+		}},
+		{MemoryPopulationIssueMixedPartNumbers, func(md *MemoryDevice) string {
+			return strings.TrimSpace(md.PartNumber)
+		}},
+	}
+	for _, c := range checks {
+		if keys := distinct(a.Populated, c.key); len(keys) > 1 {
+			add(c.kind, a.Populated, "array 0x%04X has %s", a.Array.Handle, strings.Join(keys, ", "))
+		}
+	}
+
+	for i := 1; i < len(a.Channels); i++ {
+		c, first := a.Channels[i], a.Channels[0]
+		if len(c.Populated) != len(first.Populated) || c.Capacity() != first.Capacity() {
+			var s []string
+			var mds []*MemoryDevice
+			for _, c := range a.Channels {
+				s = append(s, fmt.Sprintf("0x%04X: %d/%d populated, %s", c.Channel.Handle, len(c.Populated), len(c.Slots), formatBytes(c.Capacity())))
+				mds = append(mds, c.Populated...)
+			}
+			add(MemoryPopulationIssueUnbalancedChannels, mds, "%s", strings.Join(s, "; "))
+			break
+		}
+	}
+
+	for _, md := range a.Populated {
+		rated, conf := md.Speed, md.ConfiguredMemoryClockSpeed
+		if rated == 0 || rated == 0xFFFF || conf == 0 || conf == 0xFFFF {
+			continue
+		}
+		if conf < rated {
+			add(MemoryPopulationIssueSlowDevice, []*MemoryDevice{md},
+				"%s runs at %d MT/s, rated %d MT/s", deviceName(md), conf, rated)
+		}
+	}
+	return is
+}
+
+// GetMemoryPopulation analyzes the memory population of the SMBIOS table
+func GetMemoryPopulation() MemoryPopulation {
+	return AnalyzeMemoryPopulation(GetPhysicalMemoryArrays(), GetMemoryDevices(), GetMemoryChannels())
+}
@@ -0,0 +1,131 @@
+package godmi
+
+import "testing"
+
+func dimm(handle SMBIOSStructureHandle, size, speed, configured uint16, attributes byte, part string) *MemoryDevice {
+	return &MemoryDevice{
+		infoCommon:                 infoCommon{Handle: handle},
+		PhysicalMemoryArrayHandle:  0x1000,
+		Size:                       size,
+		Speed:                      speed,
+		ConfiguredMemoryClockSpeed: configured,
+		Attributes:                 attributes,
+		PartNumber:                 part,
+		DeviceLocator:              "DIMM",
+	}
+}
+
+func TestAnalyzeMemoryPopulation(t *testing.T) {
+	array := &PhysicalMemoryArray{infoCommon: infoCommon{Handle: 0x1000}, NumberOfMemoryDevices: 4}
+	channel := func(handle SMBIOSStructureHandle, devices ...uint16) *MemoryChannel {
+		c := &MemoryChannel{infoCommon: infoCommon{Handle: handle}}
+		for _, d := range devices {
+			c.LoadHandle = append(c.LoadHandle, MemoryDeviceLoadHandle{Load: 1, Handle: d})
+		}
+		return c
+	}
+	channels := []*MemoryChannel{channel(0x2000, 0x1100, 0x1101), channel(0x2001, 0x1102, 0x1103)}
+	empty := dimm(0x1103, 0, 0, 0, 0, "")
+
+	for _, tc := range []struct {
+		name     string
+		mds      []*MemoryDevice
+		channels []*MemoryChannel
+		want     []MemoryPopulationIssueKind
+	}{
+		{"matched", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1101, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1102, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1103, 16384, 3200, 3200, 2, "M1"),
+		}, channels, nil},
+		{"mixed", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1101, 8192, 2933, 2933, 1, "M2"),
+			dimm(0x1102, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1103, 8192, 2933, 2933, 1, "M2"),
+		}, channels, []MemoryPopulationIssueKind{
+			MemoryPopulationIssueMixedSizes,
+			MemoryPopulationIssueMixedSpeeds,
+			MemoryPopulationIssueMixedRanks,
+			MemoryPopulationIssueMixedPartNumbers,
+		}},
+		{"unbalanced and slow", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 2933, 2, "M1"),
+			dimm(0x1101, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1102, 16384, 3200, 3200, 2, "M1"),
+			empty,
+		}, channels, []MemoryPopulationIssueKind{
+			MemoryPopulationIssueUnbalancedChannels,
+			MemoryPopulationIssueSlowDevice,
+		}},
+		// Without type 37 the channels cannot be compared
+		{"no channels", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1101, 16384, 3200, 3200, 2, "M1"),
+			dimm(0x1102, 16384, 3200, 3200, 2, "M1"),
+			empty,
+		}, nil, nil},
+		// Rank and configured speed are unknown before SMBIOS 2.6 and 2.7
+		{"fields absent", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 0, 0, "M1"),
+			dimm(0x1101, 16384, 3200, 0, 2, "M1"),
+			dimm(0x1102, 16384, 3200, 0xFFFF, 0, "M1"),
+			dimm(0x1103, 16384, 0, 2933, 0, "M1"),
+		}, channels, nil},
+		{"slot count", []*MemoryDevice{
+			dimm(0x1100, 16384, 3200, 3200, 2, "M1"),
+		}, nil, []MemoryPopulationIssueKind{MemoryPopulationIssueSlotCountMismatch}},
+	} {
+		mp := AnalyzeMemoryPopulation([]*PhysicalMemoryArray{array}, tc.mds, tc.channels)
+		var got []MemoryPopulationIssueKind
+		for _, i := range mp.Issues {
+			got = append(got, i.Kind)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+// memoryDevice23 builds an SMBIOS 2.3 memory device, whose formatted area
+// ends before the attributes
+func memoryDevice23(handle uint16, locator string) []byte {
+	body := make([]byte, 0x17)
+	put16(body, 0x00, 0x1000)
+	put16(body, 0x08, 16384)
+	body[0x0C] = 1
+	body[0x0E] = byte(MemoryDeviceTypeDDR3)
+	put16(body, 0x11, 1600)
+	body[0x16] = 2
+	return structure(17, handle, body, locator, "PART-1")
+}
+
+func TestMemoryDeviceLength(t *testing.T) {
+	// The string area would otherwise be read as rank 1 and 2 and as
+	// configured speeds below 1600
+	loadTable(
+		structure(16, 0x1000, []byte{3, 3, 3, 0, 0, 0, 0, 0xFE, 0xFF, 2, 0}),
+		memoryDevice23(0x1100, "A1A1A1A1A1"),
+		memoryDevice23(0x1101, "B2B2B2B2B2"))
+	mds := GetMemoryDevices()
+	if len(mds) != 2 {
+		t.Fatalf("GetMemoryDevices: got %v", mds)
+	}
+	for _, md := range mds {
+		if md.Speed != 1600 || md.PartNumber != "PART-1" || md.Rank() != 0 ||
+			md.ConfiguredMemoryClockSpeed != 0 || md.ExtendedSize != 0 || md.ConfiguredVoltage != 0 {
+			t.Errorf("memory device 0x%04X: got %+v", md.Handle, md)
+		}
+	}
+	if mp := GetMemoryPopulation(); len(mp.Issues) != 0 {
+		t.Errorf("GetMemoryPopulation: got %v", mp.Issues)
+	}
+}
@@ -8,16 +8,17 @@ package godmi
 
 import (
 	"fmt"
+	"strings"
 )
 
 type IPMIDeviceInformationInterfaceType byte
 
 const (
-	IPMIDeviceInformationInterfaceTypeUnknown IPMIDeviceInformationInterfaceType = 1 + iota
+	IPMIDeviceInformationInterfaceTypeUnknown IPMIDeviceInformationInterfaceType = iota
 	IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle
 	IPMIDeviceInformationInterfaceTypeSMICServerManagementInterfaceChip
 	IPMIDeviceInformationInterfaceTypeBTBlockTransfer
-	IPMIDeviceInformationInterfaceTypeReservedforfutureassignmentbythisspecification
+	IPMIDeviceInformationInterfaceTypeSSIFSMBusSystemInterface
 )
 
 func (i IPMIDeviceInformationInterfaceType) String() string {
@@ -26,12 +27,25 @@ func (i IPMIDeviceInformationInterfaceType) String() string {
 		"KCS: Keyboard Controller Style",
 		"SMIC: Server Management Interface Chip",
 		"BT: Block Transfer",
-		"Reserved for future assignment by this specification",
+		"SSIF: SMBus System Interface",
 	}
-	if i <= 3 {
+	if int(i) < len(types) {
 		return types[i]
 	}
-	return types[4]
+	return OUT_OF_SPEC
+}
+
+// moduleType returns the ipmi_si name of the interface type
+func (i IPMIDeviceInformationInterfaceType) moduleType() string {
+	switch i {
+	case IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle:
+		return "kcs"
+	case IPMIDeviceInformationInterfaceTypeSMICServerManagementInterfaceChip:
+		return "smic"
+	case IPMIDeviceInformationInterfaceTypeBTBlockTransfer:
+		return "bt"
+	}
+	return ""
 }
 
 type IPMIDeviceInformationInfo byte
@@ -101,7 +115,24 @@ func (i IPMIDeviceInformationRegisterSpacing) String() string {
 		"Interface registers are on 16-byte boundaries",
 		"Reserved",
 	}
-	return space[i]
+	if int(i) < len(space) {
+		return space[i]
+	}
+	return OUT_OF_SPEC
+}
+
+// Bytes returns the distance between the interface registers in bytes, or
+// 0 if reserved
+func (i IPMIDeviceInformationRegisterSpacing) Bytes() int {
+	switch i {
+	case IPMIDeviceInformationRegisterSpacingSuccessiveByteBoundaries:
+		return 1
+	case IPMIDeviceInformationRegisterSpacing32BitBoundaries:
+		return 4
+	case IPMIDeviceInformationRegisterSpacing16ByteBoundaries:
+		return 16
+	}
+	return 0
 }
 
 type IPMIDeviceInformationLSbit byte
@@ -141,6 +172,30 @@ func newIPMIDeviceInformationAddressModiferInterruptInfo(base byte) IPMIDeviceIn
 	return ipmi
 }
 
+type IPMIDeviceInformationAddressSpace byte
+
+const (
+	IPMIDeviceInformationAddressSpaceMemory IPMIDeviceInformationAddressSpace = iota
+	IPMIDeviceInformationAddressSpaceIO
+	IPMIDeviceInformationAddressSpaceSMBus
+)
+
+func (i IPMIDeviceInformationAddressSpace) String() string {
+	spaces := [...]string{
+		"Memory-mapped",
+		"I/O",
+		"SMBus",
+	}
+	if int(i) < len(spaces) {
+		return spaces[i]
+	}
+	return OUT_OF_SPEC
+}
+
+// IPMIDeviceInformationNoNVStorage is the NV storage address of a device
+// without one
+const IPMIDeviceInformationNoNVStorage = 0xFF
+
 type IPMIDeviceInformation struct {
 	infoCommon
 	InterfaceType                  IPMIDeviceInformationInterfaceType
@@ -152,22 +207,172 @@ type IPMIDeviceInformation struct {
 	InterruptNumbe                 byte
 }
 
+// Version returns the IPMI specification revision as "major.minor"
+func (i IPMIDeviceInformation) Version() string {
+	return fmt.Sprintf("%d.%d", i.Revision>>4, i.Revision&0x0F)
+}
+
+// AddressSpace returns where the interface registers live
+func (i IPMIDeviceInformation) AddressSpace() IPMIDeviceInformationAddressSpace {
+	if i.InterfaceType == IPMIDeviceInformationInterfaceTypeSSIFSMBusSystemInterface {
+		return IPMIDeviceInformationAddressSpaceSMBus
+	}
+	if i.BaseAddress&0x01 != 0 {
+		return IPMIDeviceInformationAddressSpaceIO
+	}
+	return IPMIDeviceInformationAddressSpaceMemory
+}
+
+// Address returns the true base address of the interface: the base address
+// with its space bit replaced by the LS-bit of the modifier, or the SMBus
+// address of an SSIF interface.
+func (i IPMIDeviceInformation) Address() uint64 {
+	if i.AddressSpace() == IPMIDeviceInformationAddressSpaceSMBus {
+		return (i.BaseAddress & 0xFF) >> 1
+	}
+	return i.BaseAddress&^1 | uint64(i.BaseAddressModiferInterrutInfo.BaseAddressModifier.LSbit)
+}
+
+// RegisterSpacing returns the distance between the interface registers in
+// bytes, or 0 if reserved
+func (i IPMIDeviceInformation) RegisterSpacing() int {
+	return i.BaseAddressModiferInterrutInfo.BaseAddressModifier.RegisterSpacing.Bytes()
+}
+
+// Interrupt returns the interrupt number of the interface, or 0 if it has
+// none. The interrupt info bit only tells whether the polarity and trigger
+// mode are specified, not the number.
+func (i IPMIDeviceInformation) Interrupt() int {
+	return int(i.InterruptNumbe)
+}
+
 func (i IPMIDeviceInformation) String() string {
-	return fmt.Sprintf("IPMI Device Information\n"+
+	s := fmt.Sprintf("IPMI Device Information\n"+
 		"\tInterface Type: %s\n"+
-		"\tRevision: %d\n"+
-		"\tI2C Slave Address: %d\n"+
-		"\tNV Storage Address: %d\n"+
-		"\tBase Address: %d\n"+
-		"\tBase Address Modifer Interrut Info: %s\n"+
-		"\tInterrupt Numbe: %d",
+		"\tSpecification Version: %s\n"+
+		"\tI2C Slave Address: 0x%02x\n",
 		i.InterfaceType,
-		i.Revision,
-		i.I2CSlaveAddress,
-		i.NVStorageAddress,
-		i.BaseAddress,
-		i.BaseAddressModiferInterrutInfo,
-		i.InterruptNumbe)
+		i.Version(),
+		i.I2CSlaveAddress>>1)
+	if i.NVStorageAddress == IPMIDeviceInformationNoNVStorage {
+		s += "\tNV Storage Device: Not Present\n"
+	} else {
+		s += fmt.Sprintf("\tNV Storage Device Address: %d\n", i.NVStorageAddress)
+	}
+	if i.AddressSpace() == IPMIDeviceInformationAddressSpaceSMBus {
+		s += fmt.Sprintf("\tBase Address: 0x%02X (%s)", i.Address(), i.AddressSpace())
+	} else {
+		s += fmt.Sprintf("\tBase Address: 0x%016X (%s)\n"+
+			"\tRegister Spacing: %s",
+			i.Address(), i.AddressSpace(),
+			i.BaseAddressModiferInterrutInfo.BaseAddressModifier.RegisterSpacing)
+	}
+	if n := i.Interrupt(); n != 0 {
+		if ii := i.BaseAddressModiferInterrutInfo.InterruptInfo; ii.Info == IPMIDeviceInformationInfoSpecified {
+			s += fmt.Sprintf("\n\tInterrupt Polarity: %s\n"+
+				"\tInterrupt Trigger Mode: %s",
+				ii.Polarity,
+				ii.TriggerMode)
+		}
+		s += fmt.Sprintf("\n\tInterrupt Number: %d", n)
+	}
+	return s
+}
+
+// ModuleParameters returns the ipmi_si module parameters that describe the
+// interface, see IPMISIParameters
+func (i IPMIDeviceInformation) ModuleParameters() (string, error) {
+	return IPMISIParameters([]*IPMIDeviceInformation{&i})
 }
 
+// IPMISIParameters returns the ipmi_si module parameters for hosts where
+// ACPI and SPMI do not describe the interfaces, such as
+// "type=kcs ports=0xca2 regspacings=1". Each parameter lists one value per
+// interface, with 0 where it does not apply. SSIF interfaces are handled by
+// ipmi_ssif instead and are an error, as are unknown interface types.
+func IPMISIParameters(ds []*IPMIDeviceInformation) (string, error) {
+	if len(ds) == 0 {
+		return "", fmt.Errorf("ipmi_si: no IPMI device")
+	}
+	var types, ports, addrs, spacings, irqs []string
+	var hasPorts, hasAddrs, hasIRQs bool
+	for _, d := range ds {
+		t := d.InterfaceType.moduleType()
+		if t == "" {
+			return "", fmt.Errorf("ipmi_si: unsupported interface type %s", d.InterfaceType)
+		}
+		spacing := d.RegisterSpacing()
+		if spacing == 0 {
+			return "", fmt.Errorf("ipmi_si: reserved register spacing")
+		}
+		types = append(types, t)
+		addr := fmt.Sprintf("0x%x", d.Address())
+		if d.AddressSpace() == IPMIDeviceInformationAddressSpaceIO {
+			ports = append(ports, addr)
+			addrs = append(addrs, "0")
+			hasPorts = true
+		} else {
+			ports = append(ports, "0")
+			addrs = append(addrs, addr)
+			hasAddrs = true
+		}
+		spacings = append(spacings, fmt.Sprintf("%d", spacing))
+		irqs = append(irqs, fmt.Sprintf("%d", d.Interrupt()))
+		hasIRQs = hasIRQs || d.Interrupt() != 0
+	}
+	params := []string{"type=" + strings.Join(types, ",")}
+	if hasPorts {
+		params = append(params, "ports="+strings.Join(ports, ","))
+	}
+	if hasAddrs {
+		params = append(params, "addrs="+strings.Join(addrs, ","))
+	}
+	params = append(params, "regspacings="+strings.Join(spacings, ","))
+	if hasIRQs {
+		params = append(params, "irqs="+strings.Join(irqs, ","))
+	}
+	return strings.Join(params, " "), nil
+}
 
+func newIPMIDeviceInformation(h dmiHeader) dmiTyper {
+	data := h.data
+	i := &IPMIDeviceInformation{
+		InterfaceType:    IPMIDeviceInformationInterfaceType(data[0x04]),
+		Revision:         data[0x05],
+		I2CSlaveAddress:  data[0x06],
+		NVStorageAddress: data[0x07],
+		BaseAddress:      u64(data[0x08:0x10]),
+	}
+	if h.Length > 0x10 {
+		i.BaseAddressModiferInterrutInfo = newIPMIDeviceInformationAddressModiferInterruptInfo(data[0x10])
+	}
+	if h.Length > 0x11 {
+		i.InterruptNumbe = data[0x11]
+	}
+	return i
+}
+
+func GetIPMIDeviceInformation() *IPMIDeviceInformation {
+	if d, ok := gdmi[SMBIOSStructureTypeIPMIDevice]; ok {
+		return d.(*IPMIDeviceInformation)
+	}
+	return nil
+}
+
+func GetIPMIDeviceInformations() []*IPMIDeviceInformation {
+	var is []*IPMIDeviceInformation
+	for _, d := range GetStructures(SMBIOSStructureTypeIPMIDevice) {
+		is = append(is, d.(*IPMIDeviceInformation))
+	}
+	return is
+}
+
+// GetIPMISIParameters returns the ipmi_si module parameters for the IPMI
+// devices of the SMBIOS table
+func GetIPMISIParameters() (string, error) {
+	return IPMISIParameters(GetIPMIDeviceInformations())
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeIPMIDevice, newIPMIDeviceInformation)
+}
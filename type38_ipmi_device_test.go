@@ -0,0 +1,117 @@
+package godmi
+
+import (
+	"strings"
+	"testing"
+)
+
+func ipmiDevice(typ IPMIDeviceInformationInterfaceType, base uint64, modifier byte, irq byte) *IPMIDeviceInformation {
+	return &IPMIDeviceInformation{
+		InterfaceType:                  typ,
+		BaseAddress:                    base,
+		BaseAddressModiferInterrutInfo: newIPMIDeviceInformationAddressModiferInterruptInfo(modifier),
+		InterruptNumbe:                 irq,
+	}
+}
+
+func TestIPMIDeviceInformationAddress(t *testing.T) {
+	const (
+		kcs  = IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle
+		ssif = IPMIDeviceInformationInterfaceTypeSSIFSMBusSystemInterface
+	)
+	for _, tc := range []struct {
+		d     *IPMIDeviceInformation
+		space IPMIDeviceInformationAddressSpace
+		addr  uint64
+	}{
+		// The space bit is replaced by the LS-bit of the modifier
+		{ipmiDevice(kcs, 0xCA3, 0x00, 0), IPMIDeviceInformationAddressSpaceIO, 0xCA2},
+		{ipmiDevice(kcs, 0xCA3, 0x10, 0), IPMIDeviceInformationAddressSpaceIO, 0xCA3},
+		{ipmiDevice(kcs, 0xFED40000, 0x00, 0), IPMIDeviceInformationAddressSpaceMemory, 0xFED40000},
+		{ipmiDevice(kcs, 0xFED40000, 0x10, 0), IPMIDeviceInformationAddressSpaceMemory, 0xFED40001},
+		// SSIF holds the 8-bit SMBus address, the modifier does not apply
+		{ipmiDevice(ssif, 0x20, 0x10, 0), IPMIDeviceInformationAddressSpaceSMBus, 0x10},
+		{ipmiDevice(ssif, 0xFF21, 0x00, 0), IPMIDeviceInformationAddressSpaceSMBus, 0x10},
+	} {
+		if s, a := tc.d.AddressSpace(), tc.d.Address(); s != tc.space || a != tc.addr {
+			t.Errorf("base 0x%X modifier %d: got %s 0x%X, want %s 0x%X", tc.d.BaseAddress,
+				tc.d.BaseAddressModiferInterrutInfo.BaseAddressModifier.LSbit, s, a, tc.space, tc.addr)
+		}
+	}
+}
+
+func TestIPMISIParameters(t *testing.T) {
+	kcs := ipmiDevice(IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle, 0xCA3, 0x00, 0)
+	// 32-bit spacing, interrupt 10 specified
+	bt := ipmiDevice(IPMIDeviceInformationInterfaceTypeBTBlockTransfer, 0xFED40000, 0x48, 10)
+	// An interrupt number is used without the specified bit too
+	smic := ipmiDevice(IPMIDeviceInformationInterfaceTypeSMICServerManagementInterfaceChip, 0xCA9, 0x80, 5)
+	for _, tc := range []struct {
+		ds   []*IPMIDeviceInformation
+		want string
+	}{
+		{[]*IPMIDeviceInformation{kcs}, "type=kcs ports=0xca2 regspacings=1"},
+		{[]*IPMIDeviceInformation{bt}, "type=bt addrs=0xfed40000 regspacings=4 irqs=10"},
+		{[]*IPMIDeviceInformation{smic}, "type=smic ports=0xca8 regspacings=16 irqs=5"},
+		{[]*IPMIDeviceInformation{kcs, bt}, "type=kcs,bt ports=0xca2,0 addrs=0,0xfed40000 regspacings=1,4 irqs=0,10"},
+	} {
+		got, err := IPMISIParameters(tc.ds)
+		if err != nil || got != tc.want {
+			t.Errorf("IPMISIParameters: got %q, %v, want %q", got, err, tc.want)
+		}
+	}
+
+	for _, ds := range [][]*IPMIDeviceInformation{
+		nil,
+		{ipmiDevice(IPMIDeviceInformationInterfaceTypeSSIFSMBusSystemInterface, 0x20, 0, 0)},
+		{ipmiDevice(IPMIDeviceInformationInterfaceTypeUnknown, 0xCA3, 0, 0)},
+		{kcs, ipmiDevice(IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle, 0xCA3, 0xC0, 0)},
+	} {
+		if got, err := IPMISIParameters(ds); err == nil {
+			t.Errorf("IPMISIParameters(%v): got %q, expected error", ds, got)
+		}
+	}
+}
+
+func TestIPMIDeviceInformationLength(t *testing.T) {
+	body := []byte{byte(IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle), 0x20, 0x20, 0xFF,
+		0xA3, 0x0C, 0, 0, 0, 0, 0, 0, 0x18, 0x0A}
+	for _, tc := range []struct {
+		n    int
+		addr uint64
+		irq  int
+	}{
+		{12, 0xCA2, 0},
+		{13, 0xCA3, 0},
+		{14, 0xCA3, 10},
+	} {
+		// The string area follows the formatted area and must not be read
+		loadTable(structure(38, 0x38, body[:tc.n], "\x5a\x5a"))
+		d := GetIPMIDeviceInformation()
+		if d == nil || d.Address() != tc.addr || d.Interrupt() != tc.irq {
+			t.Errorf("length 0x%02X: got %v", tc.n+4, d)
+		}
+	}
+}
+
+func TestIPMIDeviceInformationInterrupt(t *testing.T) {
+	const kcs = IPMIDeviceInformationInterfaceTypeKCSKeyboardControllerStyle
+	for _, tc := range []struct {
+		d        *IPMIDeviceInformation
+		irq      int
+		number   bool
+		polarity bool
+	}{
+		{ipmiDevice(kcs, 0xCA3, 0x00, 0), 0, false, false},
+		// Polarity and trigger mode are only printed when specified, the
+		// number whenever it is set
+		{ipmiDevice(kcs, 0xCA3, 0x00, 7), 7, true, false},
+		{ipmiDevice(kcs, 0xCA3, 0x0A, 7), 7, true, true},
+	} {
+		s := tc.d.String()
+		if tc.d.Interrupt() != tc.irq || strings.Contains(s, "Interrupt Number: 7") != tc.number ||
+			strings.Contains(s, "Interrupt Polarity: active high") != tc.polarity {
+			t.Errorf("interrupt %d: got %d\n%s", tc.d.InterruptNumbe, tc.d.Interrupt(), s)
+		}
+	}
+}
@@ -21,7 +21,6 @@ const (
 	SystemPowerSupplyTypeUPS
 	SystemPowerSupplyTypeConverter
 	SystemPowerSupplyTypeRegulator
-	SystemPowerSupplyTypeReserved
 )
 
 func (s SystemPowerSupplyType) String() string {
@@ -34,12 +33,11 @@ func (s SystemPowerSupplyType) String() string {
 		"UPS",
 		"Converter",
 		"Regulator",
-		"Reserved",
 	}
-	if s <= 7 {
-		return types[s]
+	if s >= 1 && int(s) <= len(types) {
+		return types[s-1]
 	}
-	return types[8]
+	return OUT_OF_SPEC
 }
 
 type SystemPowerSupplyStatus byte
@@ -60,7 +58,10 @@ func (s SystemPowerSupplyStatus) String() string {
 		"Non-critical",
 		"Critical",
 	}
-	return status[s-1]
+	if s >= 1 && int(s) <= len(status) {
+		return status[s-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type SystemPowerSupplyInputVoltageSwitching byte
@@ -72,7 +73,6 @@ const (
 	SystemPowerSupplyInputVoltageSwitchingAutoSwitch
 	SystemPowerSupplyInputVoltageSwitchingWiderange
 	SystemPowerSupplyInputVoltageSwitchingNotApplicable
-	SystemPowerSupplyInputVoltageSwitchingReserved
 )
 
 func (s SystemPowerSupplyInputVoltageSwitching) String() string {
@@ -83,12 +83,11 @@ func (s SystemPowerSupplyInputVoltageSwitching) String() string {
 		"Auto-switch",
 		"Wide range",
 		"Not applicable",
-		"Reserved",
 	}
-	if s < 6 {
+	if s >= 1 && int(s) <= len(switches) {
 		return switches[s-1]
 	}
-	return switches[6]
+	return OUT_OF_SPEC
 }
 
 type SystemPowerSupplyCharacteristics struct {
@@ -104,7 +103,7 @@ func newSystemPowerSupplyCharacteristics(ch uint16) SystemPowerSupplyCharacteris
 	var sp SystemPowerSupplyCharacteristics
 	sp.DMTFPowerSupplyType = SystemPowerSupplyType((ch & 0x3c00) >> 10)
 	sp.Status = SystemPowerSupplyStatus((ch & 0x380) >> 7)
-	sp.DMTFInputVoltageSwitching = SystemPowerSupplyInputVoltageSwitching((ch & 0x70) >> 3)
+	sp.DMTFInputVoltageSwitching = SystemPowerSupplyInputVoltageSwitching((ch & 0x78) >> 3)
 	sp.IsUnpluggedFromWall = (ch&0x04 != 0)
 	sp.IsPresent = (ch&0x02 != 0)
 	sp.IsHotRepleaceable = (ch&0x01 != 0)
@@ -112,21 +111,38 @@ func newSystemPowerSupplyCharacteristics(ch uint16) SystemPowerSupplyCharacteris
 }
 
 func (s SystemPowerSupplyCharacteristics) String() string {
-	return fmt.Sprintf("System Power Supply Characteristics:\n"+
-		"\t\t\tDMTF Power Supply Type: %s\n"+
-		"\t\t\tStatus: %s\n"+
-		"\t\t\tDMTF Input Voltage Switching: %s\n"+
-		"\t\t\tIs Unplugged From Wall: %t\n"+
-		"\t\t\tIs Present: %t\n"+
-		"\t\t\tIs Hot Repleaceable: %t\n",
+	return fmt.Sprintf("\n\t\tType: %s"+
+		"\n\t\tStatus: %s"+
+		"\n\t\tInput Voltage Range Switching: %s"+
+		"\n\t\tPlugged: %s"+
+		"\n\t\tPresent: %s"+
+		"\n\t\tHot Replaceable: %s",
 		s.DMTFPowerSupplyType,
 		s.Status,
 		s.DMTFInputVoltageSwitching,
-		s.IsUnpluggedFromWall,
-		s.IsPresent,
-		s.IsHotRepleaceable)
+		yesNo(!s.IsUnpluggedFromWall),
+		yesNo(s.IsPresent),
+		yesNo(s.IsHotRepleaceable))
 }
 
+// Watts is a power in W
+type Watts uint16
+
+func (w Watts) IsKnown() bool {
+	return w != probeValueUnknown
+}
+
+func (w Watts) String() string {
+	if !w.IsKnown() {
+		return "Unknown"
+	}
+	return fmt.Sprintf("%d W", uint16(w))
+}
+
+// SystemPowerSupplyNoHandle is the value of a probe or cooling device handle
+// of a power supply that has none
+const SystemPowerSupplyNoHandle = 0xFFFF
+
 type SystemPowerSupply struct {
 	infoCommon
 	PowerUnitGroup             byte
@@ -137,28 +153,35 @@ type SystemPowerSupply struct {
 	AssetTagNumber             string
 	ModelPartNumber            string
 	RevisionLevel              string
-	MaxPowerCapacity           uint16
+	MaxPowerCapacity           Watts
 	PowerSupplyCharacteristics SystemPowerSupplyCharacteristics
 	InputVoltageProbeHandle    uint16
 	CoolingDeviceHandle        uint16
 	InputCurrentProbeHandle    uint16
 }
 
+func powerSupplyHandle(h uint16) string {
+	if h == SystemPowerSupplyNoHandle {
+		return "None"
+	}
+	return fmt.Sprintf("0x%04X", h)
+}
+
 func (s SystemPowerSupply) String() string {
 	return fmt.Sprintf("System Power Supply\n"+
 		"\tPower Unit Group: %d\n"+
 		"\tLocation: %s\n"+
-		"\tDevice Name: %s\n"+
+		"\tName: %s\n"+
 		"\tManufacturer: %s\n"+
 		"\tSerial Number: %s\n"+
-		"\tAsset Tag Number: %s\n"+
+		"\tAsset Tag: %s\n"+
 		"\tModel Part Number: %s\n"+
-		"\tRevision Level: %s\n"+
-		"\tMax Power Capacity: %d\n"+
-		"\tPower Supply Characteristics: %s\n"+
-		"\tInput Voltage Probe Handle: %d\n"+
-		"\tCooling Device Handle: %d\n"+
-		"\tInput Current Probe Handle: %d",
+		"\tRevision: %s\n"+
+		"\tMax Power Capacity: %s\n"+
+		"\tCharacteristics:%s\n"+
+		"\tInput Voltage Probe Handle: %s\n"+
+		"\tCooling Device Handle: %s\n"+
+		"\tInput Current Probe Handle: %s",
 		s.PowerUnitGroup,
 		s.Location,
 		s.DeviceName,
@@ -169,7 +192,231 @@ func (s SystemPowerSupply) String() string {
 		s.RevisionLevel,
 		s.MaxPowerCapacity,
 		s.PowerSupplyCharacteristics,
-		s.InputVoltageProbeHandle,
-		s.CoolingDeviceHandle,
-		s.InputCurrentProbeHandle)
+		powerSupplyHandle(s.InputVoltageProbeHandle),
+		powerSupplyHandle(s.CoolingDeviceHandle),
+		powerSupplyHandle(s.InputCurrentProbeHandle))
+}
+
+// InputVoltageProbe returns the probe of the input voltage, or nil
+func (s SystemPowerSupply) InputVoltageProbe() *VoltageProbe {
+	p, _ := GetStructure(SMBIOSStructureHandle(s.InputVoltageProbeHandle)).(*VoltageProbe)
+	return p
+}
+
+// CoolingDevice returns the cooling device of the power supply, or nil
+func (s SystemPowerSupply) CoolingDevice() *CoolingDevice {
+	c, _ := GetStructure(SMBIOSStructureHandle(s.CoolingDeviceHandle)).(*CoolingDevice)
+	return c
+}
+
+// InputCurrentProbe returns the probe of the input current, or nil
+func (s SystemPowerSupply) InputCurrentProbe() *ElectricalCurrentProbe {
+	p, _ := GetStructure(SMBIOSStructureHandle(s.InputCurrentProbeHandle)).(*ElectricalCurrentProbe)
+	return p
+}
+
+// Working reports whether the power supply is present, plugged in and not
+// in a critical state
+func (s SystemPowerSupply) Working() bool {
+	c := s.PowerSupplyCharacteristics
+	return c.IsPresent && !c.IsUnpluggedFromWall && c.Status != SystemPowerSupplyStatusCritical
+}
+
+func newSystemPowerSupply(h dmiHeader) dmiTyper {
+	data := h.data
+	sp := &SystemPowerSupply{
+		PowerUnitGroup:             data[0x04],
+		Location:                   h.FieldString(int(data[0x05])),
+		DeviceName:                 h.FieldString(int(data[0x06])),
+		Manufacturer:               h.FieldString(int(data[0x07])),
+		SerialNumber:               h.FieldString(int(data[0x08])),
+		AssetTagNumber:             h.FieldString(int(data[0x09])),
+		ModelPartNumber:            h.FieldString(int(data[0x0A])),
+		RevisionLevel:              h.FieldString(int(data[0x0B])),
+		MaxPowerCapacity:           Watts(u16(data[0x0C:0x0E])),
+		PowerSupplyCharacteristics: newSystemPowerSupplyCharacteristics(u16(data[0x0E:0x10])),
+		InputVoltageProbeHandle:    SystemPowerSupplyNoHandle,
+		CoolingDeviceHandle:        SystemPowerSupplyNoHandle,
+		InputCurrentProbeHandle:    SystemPowerSupplyNoHandle,
+	}
+	if h.Length > 0x15 {
+		sp.InputVoltageProbeHandle = u16(data[0x10:0x12])
+		sp.CoolingDeviceHandle = u16(data[0x12:0x14])
+		sp.InputCurrentProbeHandle = u16(data[0x14:0x16])
+	}
+	return sp
+}
+
+func GetSystemPowerSupply() *SystemPowerSupply {
+	if d, ok := gdmi[SMBIOSStructureTypePowerSupply]; ok {
+		return d.(*SystemPowerSupply)
+	}
+	return nil
+}
+
+func GetSystemPowerSupplies() []*SystemPowerSupply {
+	var ss []*SystemPowerSupply
+	for _, d := range GetStructures(SMBIOSStructureTypePowerSupply) {
+		ss = append(ss, d.(*SystemPowerSupply))
+	}
+	return ss
+}
+
+// PowerUnitGroup is a group of redundant power supplies, or a single power
+// supply that is not part of a group
+type PowerUnitGroup struct {
+	// Number is the power unit group, 0 for a supply outside any group
+	Number   byte
+	Supplies []*SystemPowerSupply
+}
+
+// Present returns the supplies installed in the group
+func (g PowerUnitGroup) Present() []*SystemPowerSupply {
+	var ss []*SystemPowerSupply
+	for _, s := range g.Supplies {
+		if s.PowerSupplyCharacteristics.IsPresent {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// Unplugged returns the installed supplies that are unplugged from the wall
+func (g PowerUnitGroup) Unplugged() []*SystemPowerSupply {
+	var ss []*SystemPowerSupply
+	for _, s := range g.Present() {
+		if s.PowerSupplyCharacteristics.IsUnpluggedFromWall {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// Working returns the supplies that deliver power
+func (g PowerUnitGroup) Working() []*SystemPowerSupply {
+	var ss []*SystemPowerSupply
+	for _, s := range g.Supplies {
+		if s.Working() {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+// Capacity returns the summed maximum power capacity of the present
+// supplies. known is false if the capacity of any of them is unknown, in
+// which case the sum covers only the known ones.
+func (g PowerUnitGroup) Capacity() (w int, known bool) {
+	known = true
+	for _, s := range g.Present() {
+		if !s.MaxPowerCapacity.IsKnown() {
+			known = false
+			continue
+		}
+		w += int(s.MaxPowerCapacity)
+	}
+	return w, known
+}
+
+// Redundant reports whether the group is N+1 redundant: it can lose any
+// one working supply and keep at least one more. The load is not known to
+// SMBIOS, see RedundantFor to take it into account.
+func (g PowerUnitGroup) Redundant() bool {
+	return g.Number != 0 && len(g.Working()) >= 2
+}
+
+// RedundantFor reports whether the group can lose its largest working
+// supply and still deliver load watts
+func (g PowerUnitGroup) RedundantFor(load int) bool {
+	if !g.Redundant() {
+		return false
+	}
+	var sum, largest int
+	for _, s := range g.Working() {
+		if !s.MaxPowerCapacity.IsKnown() {
+			return false
+		}
+		w := int(s.MaxPowerCapacity)
+		sum += w
+		if w > largest {
+			largest = w
+		}
+	}
+	return sum-largest >= load
+}
+
+func powerSupplyName(s *SystemPowerSupply) string {
+	if s.DeviceName != "" {
+		return s.DeviceName
+	}
+	return fmt.Sprintf("Power Supply 0x%04X", s.Handle)
+}
+
+func (g PowerUnitGroup) String() string {
+	var str string
+	if g.Number == 0 {
+		str = "No Power Unit Group"
+	} else {
+		str = fmt.Sprintf("Power Unit Group %d: %d/%d present", g.Number, len(g.Present()), len(g.Supplies))
+	}
+	w, known := g.Capacity()
+	if known {
+		str += fmt.Sprintf(", %d W", w)
+	} else {
+		str += fmt.Sprintf(", at least %d W", w)
+	}
+	if g.Number != 0 {
+		if g.Redundant() {
+			str += ", N+1 redundant"
+		} else {
+			str += ", not redundant"
+		}
+	}
+	for _, s := range g.Supplies {
+		c := s.PowerSupplyCharacteristics
+		state := "absent"
+		switch {
+		case c.IsPresent && c.IsUnpluggedFromWall:
+			state = "unplugged"
+		case c.IsPresent:
+			state = c.Status.String()
+		}
+		str += fmt.Sprintf("\n\t%s (%s): %s, %s", powerSupplyName(s), s.Location, s.MaxPowerCapacity, state)
+		if p := s.InputVoltageProbe(); p != nil {
+			str += fmt.Sprintf("\n\t\tInput Voltage Probe: %s", p.Description)
+		}
+		if p := s.InputCurrentProbe(); p != nil {
+			str += fmt.Sprintf("\n\t\tInput Current Probe: %s", p.Description)
+		}
+		if cd := s.CoolingDevice(); cd != nil {
+			str += fmt.Sprintf("\n\t\tCooling Device: %s", coolingDeviceName(cd))
+		}
+	}
+	return str
+}
+
+// NewPowerUnitGroups groups the power supplies by power unit group, in
+// table order. Supplies outside any group get a group of their own.
+func NewPowerUnitGroups(ss []*SystemPowerSupply) []PowerUnitGroup {
+	var gs []PowerUnitGroup
+	groups := make(map[byte]int)
+	for _, s := range ss {
+		i, ok := groups[s.PowerUnitGroup]
+		if !ok || s.PowerUnitGroup == 0 {
+			i = len(gs)
+			gs = append(gs, PowerUnitGroup{Number: s.PowerUnitGroup})
+			groups[s.PowerUnitGroup] = i
+		}
+		gs[i].Supplies = append(gs[i].Supplies, s)
+	}
+	return gs
+}
+
+// GetPowerUnitGroups groups the power supplies of the SMBIOS table
+func GetPowerUnitGroups() []PowerUnitGroup {
+	return NewPowerUnitGroups(GetSystemPowerSupplies())
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypePowerSupply, newSystemPowerSupply)
 }
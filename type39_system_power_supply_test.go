@@ -0,0 +1,95 @@
+package godmi
+
+import "testing"
+
+const (
+	psuWorking   = 0x182 // OK, present
+	psuUnplugged = 0x186 // OK, present, unplugged from the wall
+	psuCritical  = 0x282 // Critical, present
+	psuAbsent    = 0x180 // OK, not present
+)
+
+func psu(group byte, watts Watts, ch uint16) *SystemPowerSupply {
+	return &SystemPowerSupply{
+		PowerUnitGroup:             group,
+		MaxPowerCapacity:           watts,
+		PowerSupplyCharacteristics: newSystemPowerSupplyCharacteristics(ch),
+	}
+}
+
+func TestPowerUnitGroup(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		g         PowerUnitGroup
+		redundant bool
+		capacity  int
+		known     bool
+		// RedundantFor at 500 and 900 W
+		for500, for900 bool
+	}{
+		{"two working", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 800, psuWorking), psu(1, 800, psuWorking)}},
+			true, 1600, true, true, false},
+		{"three working", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 500, psuWorking), psu(1, 500, psuWorking), psu(1, 1000, psuWorking)}},
+			true, 2000, true, true, true},
+		{"one unplugged", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 800, psuWorking), psu(1, 800, psuUnplugged)}},
+			false, 1600, true, false, false},
+		{"one critical", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 800, psuWorking), psu(1, 800, psuCritical)}},
+			false, 1600, true, false, false},
+		{"one absent", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 800, psuWorking), psu(1, 800, psuAbsent)}},
+			false, 800, true, false, false},
+		// Supplies outside a group are never redundant
+		{"no group", PowerUnitGroup{0, []*SystemPowerSupply{
+			psu(0, 800, psuWorking), psu(0, 800, psuWorking)}},
+			false, 1600, true, false, false},
+		{"unknown capacity", PowerUnitGroup{1, []*SystemPowerSupply{
+			psu(1, 800, psuWorking), psu(1, probeValueUnknown, psuWorking)}},
+			true, 800, false, false, false},
+	} {
+		if r := tc.g.Redundant(); r != tc.redundant {
+			t.Errorf("%s: Redundant got %t", tc.name, r)
+		}
+		if w, known := tc.g.Capacity(); w != tc.capacity || known != tc.known {
+			t.Errorf("%s: Capacity got %d %t, want %d %t", tc.name, w, known, tc.capacity, tc.known)
+		}
+		if r5, r9 := tc.g.RedundantFor(500), tc.g.RedundantFor(900); r5 != tc.for500 || r9 != tc.for900 {
+			t.Errorf("%s: RedundantFor(500), RedundantFor(900) got %t, %t", tc.name, r5, r9)
+		}
+	}
+}
+
+func TestNewPowerUnitGroups(t *testing.T) {
+	gs := NewPowerUnitGroups([]*SystemPowerSupply{
+		psu(1, 800, psuWorking), psu(0, 400, psuWorking), psu(1, 800, psuWorking), psu(0, 400, psuWorking)})
+	if len(gs) != 3 || gs[0].Number != 1 || len(gs[0].Supplies) != 2 || len(gs[1].Supplies) != 1 || len(gs[2].Supplies) != 1 {
+		t.Errorf("NewPowerUnitGroups: got %v", gs)
+	}
+}
+
+func TestSystemPowerSupplyLength(t *testing.T) {
+	body := make([]byte, 0x12)
+	body[0] = 1
+	put16(body, 0x08, 800)
+	put16(body, 0x0A, psuWorking)
+	put16(body, 0x0C, 0x2600)
+	put16(body, 0x0E, 0x2700)
+	put16(body, 0x10, 0x2900)
+	for _, tc := range []struct {
+		n                         int
+		voltage, cooling, current uint16
+	}{
+		{0x0C, SystemPowerSupplyNoHandle, SystemPowerSupplyNoHandle, SystemPowerSupplyNoHandle},
+		{0x12, 0x2600, 0x2700, 0x2900},
+	} {
+		loadTable(structure(39, 0x39, body[:tc.n], "PSU1", "PSU2"))
+		s := GetSystemPowerSupply()
+		if s == nil || s.MaxPowerCapacity != 800 || s.InputVoltageProbeHandle != tc.voltage ||
+			s.CoolingDeviceHandle != tc.cooling || s.InputCurrentProbeHandle != tc.current {
+			t.Errorf("length 0x%02X: got %+v", tc.n+4, s)
+		}
+	}
+}
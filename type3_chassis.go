@@ -143,7 +143,10 @@ func (s ChassisSecurityStatus) String() string {
 		"ExternalInterfaceLockedOut",
 		"ExternalInterfaceEnabled",
 	}
-	return status[s-1]
+	if s >= 1 && int(s) <= len(status) {
+		return status[s-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type ChassisHeight byte
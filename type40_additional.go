@@ -7,6 +7,7 @@
 package godmi
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
@@ -18,19 +19,45 @@ type AdditionalInformationEntries struct {
 	Value            []byte
 }
 
+// ValueString formats the value the way dmidecode does: as a byte, word or
+// dword when it has that size and as a hex dump otherwise
+func (a AdditionalInformationEntries) ValueString() string {
+	switch len(a.Value) {
+	case 0:
+		return "Unavailable"
+	case 1:
+		return fmt.Sprintf("0x%02x", a.Value[0])
+	case 2:
+		return fmt.Sprintf("0x%04x", u16(a.Value))
+	case 4:
+		return fmt.Sprintf("0x%08x", u32(a.Value))
+	}
+	return fmt.Sprintf("% x", a.Value)
+}
+
+// MarshalJSON encodes Value as ValueString, so that the JSON output reads
+// like the text output
+func (a AdditionalInformationEntries) MarshalJSON() ([]byte, error) {
+	type entries AdditionalInformationEntries
+	return json.Marshal(struct {
+		entries
+		Value string
+	}{entries(a), a.ValueString()})
+}
+
 type AdditionalInformationEntriess []AdditionalInformationEntries
 
 func (a AdditionalInformationEntriess) String() string {
 	var str string
 	for _, s := range a {
-		str += fmt.Sprintf("\n\t\t\t\tReferenced Handle: %d\n"+
-			"\t\t\t\tReferenced Offset: %d\n"+
-			"\t\t\t\tString: %s\n"+
-			"\t\t\t\tValue: %v",
+		str += fmt.Sprintf("\n\t\tReferenced Handle: 0x%04x"+
+			"\n\t\tReferenced Offset: 0x%02x"+
+			"\n\t\tString: %s"+
+			"\n\t\tValue: %s",
 			s.ReferencedHandle,
 			s.ReferencedOffset,
 			s.String,
-			s.Value)
+			s.ValueString())
 	}
 	return str
 }
@@ -48,3 +75,122 @@ func (a AdditionalInformation) String() string {
 		a.NumberOfEntries,
 		AdditionalInformationEntriess(a.Entries))
 }
+
+func newAdditionalInformation(h dmiHeader) dmiTyper {
+	data := h.data
+	ai := &AdditionalInformation{
+		NumberOfEntries: data[0x04],
+	}
+	off := 0x05
+	for i := byte(0); i < ai.NumberOfEntries; i++ {
+		if off+0x05 > int(h.Length) {
+			break
+		}
+		n := int(data[off])
+		if n < 0x05 || off+n > int(h.Length) {
+			break
+		}
+		ai.Entries = append(ai.Entries, AdditionalInformationEntries{
+			Length:           data[off],
+			ReferencedHandle: u16(data[off+0x01 : off+0x03]),
+			ReferencedOffset: data[off+0x03],
+			String:           h.FieldString(int(data[off+0x04])),
+			Value:            data[off+0x05 : off+n],
+		})
+		off += n
+	}
+	return ai
+}
+
+func GetAdditionalInformation() *AdditionalInformation {
+	if d, ok := gdmi[SMBIOSStructureTypeAdditionalInformation]; ok {
+		return d.(*AdditionalInformation)
+	}
+	return nil
+}
+
+func GetAdditionalInformations() []*AdditionalInformation {
+	var as []*AdditionalInformation
+	for _, d := range GetStructures(SMBIOSStructureTypeAdditionalInformation) {
+		as = append(as, d.(*AdditionalInformation))
+	}
+	return as
+}
+
+// GetAdditionalInformationFor returns the additional information entries
+// that annotate the structure with handle h, in table order
+func GetAdditionalInformationFor(h SMBIOSStructureHandle) []AdditionalInformationEntries {
+	var es []AdditionalInformationEntries
+	for _, a := range GetAdditionalInformations() {
+		for _, e := range a.Entries {
+			if SMBIOSStructureHandle(e.ReferencedHandle) == h {
+				es = append(es, e)
+			}
+		}
+	}
+	return es
+}
+
+// GetAdditionalInformationAt returns the additional information entries
+// that annotate the field at offset of the structure with handle h
+func GetAdditionalInformationAt(h SMBIOSStructureHandle, offset byte) []AdditionalInformationEntries {
+	var es []AdditionalInformationEntries
+	for _, e := range GetAdditionalInformationFor(h) {
+		if e.ReferencedOffset == offset {
+			es = append(es, e)
+		}
+	}
+	return es
+}
+
+// AnnotatedStructure is a decoded structure together with the additional
+// information entries that reference it
+type AnnotatedStructure struct {
+	Structure             interface{}
+	AdditionalInformation []AdditionalInformationEntries
+}
+
+// Annotate attaches the additional information of the table to s, a
+// structure returned by one of the getters
+func Annotate(s interface{}) AnnotatedStructure {
+	a := AnnotatedStructure{Structure: s}
+	if ic, ok := s.(infoCommoner); ok {
+		a.AdditionalInformation = GetAdditionalInformationFor(ic.common().Handle)
+	}
+	return a
+}
+
+func (a AnnotatedStructure) String() string {
+	s := fmt.Sprint(a.Structure)
+	if len(a.AdditionalInformation) == 0 {
+		return s
+	}
+	s += "\n\tAdditional Information:"
+	for _, e := range a.AdditionalInformation {
+		s += fmt.Sprintf("\n\t\tOffset 0x%02x: %s: %s", e.ReferencedOffset, e.String, e.ValueString())
+	}
+	return s
+}
+
+// MarshalJSON encodes the structure with its entries inline, under the
+// AdditionalInformation key
+func (a AnnotatedStructure) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(a.Structure)
+	if err != nil || len(a.AdditionalInformation) == 0 {
+		return b, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	es, err := json.Marshal(a.AdditionalInformation)
+	if err != nil {
+		return nil, err
+	}
+	m["AdditionalInformation"] = es
+	return json.Marshal(m)
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeAdditionalInformation, newAdditionalInformation)
+}
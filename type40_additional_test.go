@@ -0,0 +1,116 @@
+package godmi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// additionalEntry builds an entry of an additional information structure
+func additionalEntry(handle uint16, offset, str byte, value ...byte) []byte {
+	e := []byte{byte(5 + len(value)), 0, 0, offset, str}
+	put16(e, 1, handle)
+	return append(e, value...)
+}
+
+func additionalInformation(n byte, es ...[]byte) []byte {
+	b := []byte{n}
+	for _, e := range es {
+		b = append(b, e...)
+	}
+	return b
+}
+
+func TestAdditionalInformationDecode(t *testing.T) {
+	loadTable(structure(40, 0x40, additionalInformation(3,
+		additionalEntry(0x0009, 0x05, 1, 0xAA),
+		additionalEntry(0x0011, 0x10, 2, 0x34, 0x12),
+		additionalEntry(0x0011, 0x0C, 0, 1, 2, 3)),
+		"Slot Label", "DIMM Label"))
+	a := GetAdditionalInformation()
+	if a == nil || a.NumberOfEntries != 3 || len(a.Entries) != 3 {
+		t.Fatalf("GetAdditionalInformation: got %v", a)
+	}
+	for i, want := range []struct {
+		handle uint16
+		offset byte
+		str    string
+		value  string
+	}{
+		{0x0009, 0x05, "Slot Label", "0xaa"},
+		{0x0011, 0x10, "DIMM Label", "0x1234"},
+		{0x0011, 0x0C, "Not Specified", "01 02 03"},
+	} {
+		e := a.Entries[i]
+		if e.ReferencedHandle != want.handle || e.ReferencedOffset != want.offset ||
+			e.String != want.str || e.ValueString() != want.value {
+			t.Errorf("entry %d: got %+v %s", i, e, e.ValueString())
+		}
+	}
+}
+
+func TestAdditionalInformationMalformed(t *testing.T) {
+	short := additionalEntry(0x0009, 0x05, 0, 0xAA)
+	short[0] = 4
+	long := additionalEntry(0x0009, 0x05, 0, 0xAA)
+	long[0] = 9
+	for _, tc := range []struct {
+		name string
+		body []byte
+		n    int
+	}{
+		// The count claims more entries than the structure holds
+		{"count", additionalInformation(2, additionalEntry(0x0009, 0x05, 0, 0xAA)), 1},
+		{"entry shorter than its header", additionalInformation(2, short, additionalEntry(0x0009, 0x06, 0)), 0},
+		{"entry past the structure", additionalInformation(1, long), 0},
+		{"no value", additionalInformation(1, additionalEntry(0x0009, 0x05, 0)), 1},
+	} {
+		loadTable(structure(40, 0x40, tc.body))
+		a := GetAdditionalInformation()
+		if a == nil || len(a.Entries) != tc.n {
+			t.Errorf("%s: got %v", tc.name, a)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	loadTable(
+		structure(13, 0x0D, make([]byte, 0x12)),
+		structure(40, 0x40, additionalInformation(2,
+			additionalEntry(0x0D, 0x04, 1, 0xAA),
+			additionalEntry(0x99, 0x04, 1, 0xBB)),
+			"Languages"),
+		structure(40, 0x41, additionalInformation(1,
+			additionalEntry(0x0D, 0x15, 0, 0x01, 0x00)), "x"))
+	s := GetStructure(0x0D)
+	a := Annotate(s)
+	if a.Structure != s || len(a.AdditionalInformation) != 2 ||
+		a.AdditionalInformation[0].ValueString() != "0xaa" || a.AdditionalInformation[1].ReferencedOffset != 0x15 {
+		t.Fatalf("Annotate: got %v", a.AdditionalInformation)
+	}
+	if es := GetAdditionalInformationAt(0x0D, 0x15); len(es) != 1 || es[0].ValueString() != "0x0001" {
+		t.Errorf("GetAdditionalInformationAt: got %v", es)
+	}
+	if len(Annotate(GetStructure(0x40)).AdditionalInformation) != 0 {
+		t.Error("Annotate: unreferenced structure got entries")
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m struct {
+		Handle                SMBIOSStructureHandle
+		AdditionalInformation []struct {
+			ReferencedOffset byte
+			String           string
+			Value            string
+		}
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	ai := m.AdditionalInformation
+	if m.Handle != 0x0D || len(ai) != 2 || ai[0].String != "Languages" || ai[0].Value != "0xaa" || ai[1].Value != "0x0001" {
+		t.Errorf("MarshalJSON: got %s", b)
+	}
+}
@@ -38,13 +38,17 @@ func (o OnBoardDevicesExtendedInformationType) String() string {
 		"SATA Controller",
 		"SAS Controller",
 	}
-	return types[o-1]
+	if o >= OnBoardDevicesExtendedInformationTypeOther && o <= OnBoardDevicesExtendedInformationTypeSASController {
+		return types[o-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type OnBoardDevicesExtendedInformation struct {
 	infoCommon
 	ReferenceDesignation string
 	DeviceType           OnBoardDevicesExtendedInformationType
+	Enabled              bool
 	DeviceTypeInstance   byte
 	SegmentGroupNumber   uint16
 	BusNumber            byte
@@ -52,24 +56,60 @@ type OnBoardDevicesExtendedInformation struct {
 }
 
 func (o OnBoardDevicesExtendedInformation) SlotSegment() string {
-	if o.SegmentGroupNumber == 0xFFFF || o.BusNumber == 0xFF || o.DeviceFunctionNumber == 0xFF {
-		return "Not of types PCI/AGP/PCI-X/PCI-Express"
+	if a := o.BusAddress(); a != "" {
+		return "Bus Address: " + a
 	}
-	return fmt.Sprintf("Bus Address: %04x:%02x:%02x.%x",
-		o.SegmentGroupNumber,
-		o.BusNumber,
-		o.DeviceFunctionNumber>>3,
-		o.DeviceFunctionNumber&0x7)
+	return "Not of types PCI/AGP/PCI-X/PCI-Express"
+}
+
+// BusAddress returns the PCI address of the device as segment:bus:device.function,
+// or an empty string if the device has none
+func (o OnBoardDevicesExtendedInformation) BusAddress() string {
+	return busAddress(o.SegmentGroupNumber, o.BusNumber, o.DeviceFunctionNumber)
 }
 
 func (o OnBoardDevicesExtendedInformation) String() string {
 	return fmt.Sprintf("On Board Devices Extended Information\n"+
 		"\tReference Designation: %s\n"+
 		"\tDevice Type: %s\n"+
+		"\tDevice Status: %s\n"+
 		"\tDevice Type Instance: %d\n"+
-		"%s\n",
+		"\t%s",
 		o.ReferenceDesignation,
 		o.DeviceType,
+		enabled(o.Enabled),
 		o.DeviceTypeInstance,
 		o.SlotSegment())
 }
+
+func newOnBoardDevicesExtendedInformation(h dmiHeader) dmiTyper {
+	data := h.data
+	return &OnBoardDevicesExtendedInformation{
+		ReferenceDesignation: h.FieldString(int(data[0x04])),
+		DeviceType:           OnBoardDevicesExtendedInformationType(data[0x05] & 0x7F),
+		Enabled:              data[0x05]&0x80 != 0,
+		DeviceTypeInstance:   data[0x06],
+		SegmentGroupNumber:   u16(data[0x07:0x09]),
+		BusNumber:            data[0x09],
+		DeviceFunctionNumber: data[0x0A],
+	}
+}
+
+func GetOnBoardDevicesExtendedInformation() *OnBoardDevicesExtendedInformation {
+	if d, ok := gdmi[SMBIOSStructureTypeOnBoardDevicesExtendedInformation]; ok {
+		return d.(*OnBoardDevicesExtendedInformation)
+	}
+	return nil
+}
+
+func GetOnBoardDevicesExtendedInformations() []*OnBoardDevicesExtendedInformation {
+	var os []*OnBoardDevicesExtendedInformation
+	for _, d := range GetStructures(SMBIOSStructureTypeOnBoardDevicesExtendedInformation) {
+		os = append(os, d.(*OnBoardDevicesExtendedInformation))
+	}
+	return os
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeOnBoardDevicesExtendedInformation, newOnBoardDevicesExtendedInformation)
+}
@@ -8,6 +8,8 @@ package godmi
 
 import (
 	"fmt"
+	"net"
+	"strings"
 )
 
 type ManagementControllerHostInterfaceType byte
@@ -22,6 +24,11 @@ const (
 	ManagementControllerHostInterfaceType16850_16850AUARTRegisterCompatible
 )
 
+const (
+	ManagementControllerHostInterfaceTypeNetworkHostInterface ManagementControllerHostInterfaceType = 0x40
+	ManagementControllerHostInterfaceTypeOEM                  ManagementControllerHostInterfaceType = 0xF0
+)
+
 func (m ManagementControllerHostInterfaceType) String() string {
 	types := [...]string{
 		"KCS: Keyboard Controller Style",
@@ -35,10 +42,343 @@ func (m ManagementControllerHostInterfaceType) String() string {
 	if m >= 0x02 && m <= 0x08 {
 		return types[m-0x02]
 	}
-	if m == 0xf0 {
+	switch m {
+	case ManagementControllerHostInterfaceTypeNetworkHostInterface:
+		return "Network"
+	case ManagementControllerHostInterfaceTypeOEM:
+		return "OEM"
+	}
+	return OUT_OF_SPEC
+}
+
+type NetworkHostInterfaceDeviceType byte
+
+const (
+	NetworkHostInterfaceDeviceTypeUSB NetworkHostInterfaceDeviceType = 0x02 + iota
+	NetworkHostInterfaceDeviceTypePCI
+	NetworkHostInterfaceDeviceTypeUSBV2
+	NetworkHostInterfaceDeviceTypePCIV2
+)
+
+func (n NetworkHostInterfaceDeviceType) String() string {
+	types := [...]string{
+		"USB",
+		"PCI/PCIe",
+		"USB v2",
+		"PCI/PCIe v2",
+	}
+	if n >= 0x02 && n <= 0x05 {
+		return types[n-0x02]
+	}
+	if n >= 0x80 {
+		return "OEM"
+	}
+	return OUT_OF_SPEC
+}
+
+// NetworkHostInterfaceDevice describes the device of a network host
+// interface. USB devices set VendorID and DeviceID to idVendor and
+// idProduct; only the v2 descriptors carry a MAC address and, for PCI, a
+// bus address.
+type NetworkHostInterfaceDevice struct {
+	Type                 NetworkHostInterfaceDeviceType
+	VendorID             uint16
+	DeviceID             uint16
+	SubVendorID          uint16
+	SubDeviceID          uint16
+	SerialNumber         string
+	MACAddress           net.HardwareAddr
+	SegmentGroupNumber   uint16
+	BusNumber            byte
+	DeviceFunctionNumber byte
+	// OEMVendorID is the IANA enterprise number of an OEM device
+	OEMVendorID uint32
+	Data        []byte
+}
+
+// BusAddress returns the PCI address of a PCI v2 device, or "" otherwise
+func (n NetworkHostInterfaceDevice) BusAddress() string {
+	if n.Type != NetworkHostInterfaceDeviceTypePCIV2 {
+		return ""
+	}
+	return busAddress(n.SegmentGroupNumber, n.BusNumber, n.DeviceFunctionNumber)
+}
+
+func (n NetworkHostInterfaceDevice) String() string {
+	s := fmt.Sprintf("\n\t\tDevice Type: %s", n.Type)
+	switch n.Type {
+	case NetworkHostInterfaceDeviceTypeUSB, NetworkHostInterfaceDeviceTypeUSBV2:
+		s += fmt.Sprintf("\n\t\tidVendor: 0x%04x\n\t\tidProduct: 0x%04x", n.VendorID, n.DeviceID)
+		if n.SerialNumber != "" {
+			s += "\n\t\tSerial Number: " + n.SerialNumber
+		}
+	case NetworkHostInterfaceDeviceTypePCI, NetworkHostInterfaceDeviceTypePCIV2:
+		s += fmt.Sprintf("\n\t\tVendorID: 0x%04x\n\t\tDeviceID: 0x%04x"+
+			"\n\t\tSubVendorID: 0x%04x\n\t\tSubDeviceID: 0x%04x",
+			n.VendorID, n.DeviceID, n.SubVendorID, n.SubDeviceID)
+		if a := n.BusAddress(); a != "" {
+			s += "\n\t\tBus Address: " + a
+		}
+	default:
+		if n.Type >= 0x80 {
+			s += fmt.Sprintf("\n\t\tVendor ID: 0x%08X", n.OEMVendorID)
+		}
+	}
+	if n.MACAddress != nil {
+		s += "\n\t\tMAC Address: " + n.MACAddress.String()
+	}
+	return s
+}
+
+// usbSerialNumber decodes a USB string descriptor: a length, the descriptor
+// type 0x03 and UTF-16LE characters
+func usbSerialNumber(d []byte) string {
+	if len(d) < 2 || d[1] != 0x03 {
+		return ""
+	}
+	n := int(d[0])
+	if n > len(d) {
+		n = len(d)
+	}
+	var rs []rune
+	for i := 2; i+1 < n; i += 2 {
+		rs = append(rs, rune(u16(d[i:i+2])))
+	}
+	return string(rs)
+}
+
+func newNetworkHostInterfaceDevice(h dmiHeader, d []byte) *NetworkHostInterfaceDevice {
+	if len(d) < 1 {
+		return nil
+	}
+	n := &NetworkHostInterfaceDevice{
+		Type: NetworkHostInterfaceDeviceType(d[0]),
+		Data: d[1:],
+	}
+	d = d[1:]
+	switch n.Type {
+	case NetworkHostInterfaceDeviceTypeUSB:
+		if len(d) >= 4 {
+			n.VendorID = u16(d[0:2])
+			n.DeviceID = u16(d[2:4])
+			n.SerialNumber = usbSerialNumber(d[4:])
+		}
+	case NetworkHostInterfaceDeviceTypePCI:
+		if len(d) >= 8 {
+			n.VendorID = u16(d[0:2])
+			n.DeviceID = u16(d[2:4])
+			n.SubVendorID = u16(d[4:6])
+			n.SubDeviceID = u16(d[6:8])
+		}
+	case NetworkHostInterfaceDeviceTypeUSBV2:
+		// d[0] is the length of the descriptor
+		if len(d) >= 12 {
+			n.VendorID = u16(d[1:3])
+			n.DeviceID = u16(d[3:5])
+			n.SerialNumber = h.FieldString(int(d[5]))
+			n.MACAddress = net.HardwareAddr(d[6:12])
+		}
+	case NetworkHostInterfaceDeviceTypePCIV2:
+		if len(d) >= 19 {
+			n.VendorID = u16(d[1:3])
+			n.DeviceID = u16(d[3:5])
+			n.SubVendorID = u16(d[5:7])
+			n.SubDeviceID = u16(d[7:9])
+			n.MACAddress = net.HardwareAddr(d[9:15])
+			n.SegmentGroupNumber = u16(d[15:17])
+			n.BusNumber = d[17]
+			n.DeviceFunctionNumber = d[18]
+		}
+	default:
+		if n.Type >= 0x80 && len(d) >= 4 {
+			n.OEMVendorID = u32(d[0:4])
+		}
+	}
+	return n
+}
+
+type HostInterfaceProtocolType byte
+
+const (
+	HostInterfaceProtocolTypeIPMI          HostInterfaceProtocolType = 0x02
+	HostInterfaceProtocolTypeMCTP          HostInterfaceProtocolType = 0x03
+	HostInterfaceProtocolTypeRedfishOverIP HostInterfaceProtocolType = 0x04
+	HostInterfaceProtocolTypeOEM           HostInterfaceProtocolType = 0xF0
+)
+
+func (h HostInterfaceProtocolType) String() string {
+	switch h {
+	case HostInterfaceProtocolTypeIPMI:
+		return "IPMI"
+	case HostInterfaceProtocolTypeMCTP:
+		return "MCTP"
+	case HostInterfaceProtocolTypeRedfishOverIP:
+		return "Redfish over IP"
+	case HostInterfaceProtocolTypeOEM:
 		return "OEM"
 	}
-	return "<OUT OF SPEC>"
+	return OUT_OF_SPEC
+}
+
+type RedfishIPAssignmentType byte
+
+const (
+	RedfishIPAssignmentTypeUnknown RedfishIPAssignmentType = iota
+	RedfishIPAssignmentTypeStatic
+	RedfishIPAssignmentTypeDHCP
+	RedfishIPAssignmentTypeAutoConfigure
+	RedfishIPAssignmentTypeHostSelected
+)
+
+func (r RedfishIPAssignmentType) String() string {
+	types := [...]string{
+		"Unknown",
+		"Static",
+		"DHCP",
+		"AutoConf",
+		"Host Selected",
+	}
+	if int(r) < len(types) {
+		return types[r]
+	}
+	return OUT_OF_SPEC
+}
+
+type RedfishIPAddressFormat byte
+
+const (
+	RedfishIPAddressFormatUnknown RedfishIPAddressFormat = iota
+	RedfishIPAddressFormatIPv4
+	RedfishIPAddressFormatIPv6
+)
+
+func (r RedfishIPAddressFormat) String() string {
+	formats := [...]string{
+		"Unknown",
+		"IPv4",
+		"IPv6",
+	}
+	if int(r) < len(formats) {
+		return formats[r]
+	}
+	return OUT_OF_SPEC
+}
+
+// redfishIP decodes a 16 byte address field, of which IPv4 uses the first
+// four bytes
+func redfishIP(f RedfishIPAddressFormat, d []byte) net.IP {
+	switch f {
+	case RedfishIPAddressFormatIPv4:
+		return net.IPv4(d[0], d[1], d[2], d[3])
+	case RedfishIPAddressFormatIPv6:
+		return net.IP(append([]byte(nil), d[:16]...))
+	}
+	return nil
+}
+
+// RedfishOverIP is the protocol record of a Redfish service reachable over
+// the network host interface
+type RedfishOverIP struct {
+	ServiceUUID            string
+	HostIPAssignmentType   RedfishIPAssignmentType
+	HostIPAddressFormat    RedfishIPAddressFormat
+	HostIPAddress          net.IP
+	HostIPMask             net.IP
+	ServiceIPDiscoveryType RedfishIPAssignmentType
+	ServiceIPAddressFormat RedfishIPAddressFormat
+	ServiceIPAddress       net.IP
+	ServiceIPMask          net.IP
+	ServicePort            uint16
+	ServiceVLANID          uint32
+	ServiceHostname        string
+}
+
+// URL returns the address of the Redfish service root. The host name is
+// preferred, as the service certificate is normally issued for it.
+func (r RedfishOverIP) URL() string {
+	host := r.ServiceHostname
+	if host == "" && r.ServiceIPAddress != nil {
+		host = r.ServiceIPAddress.String()
+	}
+	if host == "" {
+		return ""
+	}
+	if r.ServicePort != 0 && r.ServicePort != 443 {
+		host = net.JoinHostPort(host, fmt.Sprintf("%d", r.ServicePort))
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return "https://" + host + "/redfish/v1/"
+}
+
+func (r RedfishOverIP) String() string {
+	return fmt.Sprintf("\n\t\tService UUID: %s"+
+		"\n\t\tHost IP Assignment Type: %s"+
+		"\n\t\tHost IP Address Format: %s"+
+		"\n\t\tIP Address: %s"+
+		"\n\t\tIP Mask: %s"+
+		"\n\t\tRedfish Service IP Discovery Type: %s"+
+		"\n\t\tRedfish Service IP Address Format: %s"+
+		"\n\t\tRedfish Service IP Address: %s"+
+		"\n\t\tRedfish Service IP Mask: %s"+
+		"\n\t\tRedfish Service Port: %d"+
+		"\n\t\tRedfish Service Vlan: %d"+
+		"\n\t\tRedfish Service Hostname: %s",
+		r.ServiceUUID,
+		r.HostIPAssignmentType,
+		r.HostIPAddressFormat,
+		r.HostIPAddress,
+		r.HostIPMask,
+		r.ServiceIPDiscoveryType,
+		r.ServiceIPAddressFormat,
+		r.ServiceIPAddress,
+		r.ServiceIPMask,
+		r.ServicePort,
+		r.ServiceVLANID,
+		r.ServiceHostname)
+}
+
+func newRedfishOverIP(d []byte) *RedfishOverIP {
+	if len(d) < 0x5B {
+		return nil
+	}
+	r := &RedfishOverIP{
+		ServiceUUID: fmt.Sprintf("%02x%02x%02x%02x-%02x%02x-%02x%02x-%02x%02x-%02x%02x%02x%02x%02x%02x",
+			d[3], d[2], d[1], d[0], d[5], d[4], d[7], d[6],
+			d[8], d[9], d[10], d[11], d[12], d[13], d[14], d[15]),
+		HostIPAssignmentType:   RedfishIPAssignmentType(d[0x10]),
+		HostIPAddressFormat:    RedfishIPAddressFormat(d[0x11]),
+		ServiceIPDiscoveryType: RedfishIPAssignmentType(d[0x32]),
+		ServiceIPAddressFormat: RedfishIPAddressFormat(d[0x33]),
+		ServicePort:            u16(d[0x54:0x56]),
+		ServiceVLANID:          u32(d[0x56:0x5A]),
+	}
+	r.HostIPAddress = redfishIP(r.HostIPAddressFormat, d[0x12:0x22])
+	r.HostIPMask = redfishIP(r.HostIPAddressFormat, d[0x22:0x32])
+	r.ServiceIPAddress = redfishIP(r.ServiceIPAddressFormat, d[0x34:0x44])
+	r.ServiceIPMask = redfishIP(r.ServiceIPAddressFormat, d[0x44:0x54])
+	n := int(d[0x5A])
+	if 0x5B+n <= len(d) {
+		r.ServiceHostname = strings.TrimRight(string(d[0x5B:0x5B+n]), "\x00")
+	}
+	return r
+}
+
+// HostInterfaceProtocolRecord is a protocol the management controller
+// offers over the interface
+type HostInterfaceProtocolRecord struct {
+	Type HostInterfaceProtocolType
+	Data []byte
+	// RedfishOverIP is set for Redfish over IP records
+	RedfishOverIP *RedfishOverIP
+}
+
+func (h HostInterfaceProtocolRecord) String() string {
+	s := fmt.Sprintf("\n\tProtocol ID: %02x (%s)", byte(h.Type), h.Type)
+	if h.RedfishOverIP != nil {
+		s += h.RedfishOverIP.String()
+	}
+	return s
 }
 
 type ManagementControllerHostInterfaceData []byte
@@ -47,20 +387,109 @@ type ManagementControllerHostInterface struct {
 	infoCommon
 	Type ManagementControllerHostInterfaceType
 	Data ManagementControllerHostInterfaceData
+	// Device is set for network host interfaces
+	Device    *NetworkHostInterfaceDevice
+	Protocols []HostInterfaceProtocolRecord
 }
 
 func (m ManagementControllerHostInterface) MCHostInterfaceData() string {
-	if m.Type == 0xF0 {
+	if m.Type == ManagementControllerHostInterfaceTypeOEM && len(m.Data) >= 4 {
 		return fmt.Sprintf("Vendor ID:0x%02X%02X%02X%02X",
-			m.Data[0x01], m.Data[0x02], m.Data[0x03], m.Data[0x04])
+			m.Data[0x00], m.Data[0x01], m.Data[0x02], m.Data[0x03])
+	}
+	if m.Device != nil {
+		return m.Device.String()
 	}
 	return ""
 }
 
+// RedfishServices returns the Redfish over IP records of the interface
+func (m ManagementControllerHostInterface) RedfishServices() []*RedfishOverIP {
+	var rs []*RedfishOverIP
+	for _, p := range m.Protocols {
+		if p.RedfishOverIP != nil {
+			rs = append(rs, p.RedfishOverIP)
+		}
+	}
+	return rs
+}
+
 func (m ManagementControllerHostInterface) String() string {
-	return fmt.Sprintf("Management Controller Host Interface\n"+
+	s := fmt.Sprintf("Management Controller Host Interface\n"+
 		"\tType: %s\n"+
-		"\tMC Host Interface Data: %s\n",
+		"\tMC Host Interface Data: %s",
 		m.Type,
-		m.MCHostInterfaceData)
+		m.MCHostInterfaceData())
+	for _, p := range m.Protocols {
+		s += p.String()
+	}
+	return s
+}
+
+func newManagementControllerHostInterface(h dmiHeader) dmiTyper {
+	data := h.data
+	mc := &ManagementControllerHostInterface{
+		Type: ManagementControllerHostInterfaceType(data[0x04]),
+	}
+	length := int(h.Length)
+	if length < 0x06 {
+		return mc
+	}
+	n := int(data[0x05])
+	if 0x06+n > length {
+		return mc
+	}
+	mc.Data = data[0x06 : 0x06+n]
+	if mc.Type == ManagementControllerHostInterfaceTypeNetworkHostInterface {
+		mc.Device = newNetworkHostInterfaceDevice(h, mc.Data)
+	}
+	off := 0x06 + n
+	if off >= length {
+		return mc
+	}
+	count := int(data[off])
+	off++
+	for i := 0; i < count && off+2 <= length; i++ {
+		p := HostInterfaceProtocolRecord{Type: HostInterfaceProtocolType(data[off])}
+		l := int(data[off+1])
+		if off+2+l > length {
+			break
+		}
+		p.Data = data[off+2 : off+2+l]
+		if p.Type == HostInterfaceProtocolTypeRedfishOverIP {
+			p.RedfishOverIP = newRedfishOverIP(p.Data)
+		}
+		mc.Protocols = append(mc.Protocols, p)
+		off += 2 + l
+	}
+	return mc
+}
+
+func GetManagementControllerHostInterface() *ManagementControllerHostInterface {
+	if d, ok := gdmi[SMBIOSStructureTypeManagementControllerHostInterface]; ok {
+		return d.(*ManagementControllerHostInterface)
+	}
+	return nil
+}
+
+func GetManagementControllerHostInterfaces() []*ManagementControllerHostInterface {
+	var ms []*ManagementControllerHostInterface
+	for _, d := range GetStructures(SMBIOSStructureTypeManagementControllerHostInterface) {
+		ms = append(ms, d.(*ManagementControllerHostInterface))
+	}
+	return ms
+}
+
+// GetRedfishServices returns every Redfish over IP service the management
+// controllers of the SMBIOS table advertise
+func GetRedfishServices() []*RedfishOverIP {
+	var rs []*RedfishOverIP
+	for _, m := range GetManagementControllerHostInterfaces() {
+		rs = append(rs, m.RedfishServices()...)
+	}
+	return rs
+}
+
+func init() {
+	addTypeFunc(SMBIOSStructureTypeManagementControllerHostInterface, newManagementControllerHostInterface)
 }
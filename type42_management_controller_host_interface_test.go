@@ -0,0 +1,166 @@
+package godmi
+
+import (
+	"net"
+	"testing"
+)
+
+// redfishRecord builds the data of a Redfish over IP protocol record
+func redfishRecord(format byte, host, service net.IP, port uint16, vlan uint32, hostname string) []byte {
+	d := make([]byte, 0x5B)
+	copy(d, []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff})
+	d[0x10] = byte(RedfishIPAssignmentTypeStatic)
+	d[0x11] = format
+	d[0x32] = byte(RedfishIPAssignmentTypeDHCP)
+	d[0x33] = format
+	ip := func(off int, a net.IP) {
+		if v4 := a.To4(); format == byte(RedfishIPAddressFormatIPv4) {
+			copy(d[off:], v4)
+		} else {
+			copy(d[off:], a.To16())
+		}
+	}
+	ip(0x12, host)
+	ip(0x34, service)
+	put16(d, 0x54, port)
+	d[0x56], d[0x57], d[0x58], d[0x59] = byte(vlan), byte(vlan>>8), byte(vlan>>16), byte(vlan>>24)
+	d[0x5A] = byte(len(hostname))
+	return append(d, hostname...)
+}
+
+func networkHostInterface(device []byte, records ...[]byte) []byte {
+	b := []byte{byte(ManagementControllerHostInterfaceTypeNetworkHostInterface), byte(len(device))}
+	b = append(b, device...)
+	b = append(b, byte(len(records)))
+	for _, r := range records {
+		b = append(b, r...)
+	}
+	return b
+}
+
+func protocolRecord(typ HostInterfaceProtocolType, data []byte) []byte {
+	return append([]byte{byte(typ), byte(len(data))}, data...)
+}
+
+func TestManagementControllerHostInterfacePCIV2(t *testing.T) {
+	dev := []byte{byte(NetworkHostInterfaceDeviceTypePCIV2), 0x17,
+		0x86, 0x80, 0x33, 0x15, 0x86, 0x80, 0x01, 0x00,
+		0x02, 0x11, 0x22, 0x33, 0x44, 0x55,
+		0x01, 0x00, 0x3b, 0x0a}
+	rf := redfishRecord(byte(RedfishIPAddressFormatIPv4), net.IPv4(169, 254, 0, 2), net.IPv4(169, 254, 0, 1), 443, 7, "bmc.local")
+	loadTable(structure(42, 0x50, networkHostInterface(dev,
+		protocolRecord(HostInterfaceProtocolTypeRedfishOverIP, rf),
+		protocolRecord(HostInterfaceProtocolTypeIPMI, nil))))
+
+	m := GetManagementControllerHostInterface()
+	if m == nil || m.Device == nil {
+		t.Fatalf("no network host interface: %v", m)
+	}
+	d := m.Device
+	if d.VendorID != 0x8086 || d.DeviceID != 0x1533 || d.SubDeviceID != 0x0001 ||
+		d.MACAddress.String() != "02:11:22:33:44:55" || d.BusAddress() != "0001:3b:01.2" {
+		t.Errorf("PCI v2 device: got %+v", d)
+	}
+	if len(m.Protocols) != 2 || m.Protocols[1].Type != HostInterfaceProtocolTypeIPMI || m.Protocols[1].RedfishOverIP != nil {
+		t.Fatalf("protocol records: got %v", m.Protocols)
+	}
+	rs := GetRedfishServices()
+	if len(rs) != 1 {
+		t.Fatalf("GetRedfishServices: got %v", rs)
+	}
+	r := rs[0]
+	if r.ServiceUUID != "00112233-4455-6677-8899-aabbccddeeff" ||
+		r.HostIPAssignmentType != RedfishIPAssignmentTypeStatic ||
+		r.ServiceIPDiscoveryType != RedfishIPAssignmentTypeDHCP ||
+		r.HostIPAddress.String() != "169.254.0.2" || r.ServiceIPAddress.String() != "169.254.0.1" ||
+		r.ServicePort != 443 || r.ServiceVLANID != 7 || r.ServiceHostname != "bmc.local" {
+		t.Errorf("Redfish over IP: got %+v", r)
+	}
+	if u := r.URL(); u != "https://bmc.local/redfish/v1/" {
+		t.Errorf("URL: got %q", u)
+	}
+}
+
+func TestManagementControllerHostInterfaceUSB(t *testing.T) {
+	// USB v2 names its serial number by string index, after a length byte
+	v2 := []byte{byte(NetworkHostInterfaceDeviceTypeUSBV2), 0x0C,
+		0x6b, 0x1d, 0x03, 0x01, 0x01,
+		0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	// USB v1 carries the serial number as a string descriptor
+	v1 := []byte{byte(NetworkHostInterfaceDeviceTypeUSB),
+		0x6b, 0x1d, 0x03, 0x01,
+		0x08, 0x03, 'S', 0, 'N', 0, '1', 0}
+	loadTable(
+		structure(42, 0x50, networkHostInterface(v2), "SER-2"),
+		structure(42, 0x51, networkHostInterface(v1)))
+	ms := GetManagementControllerHostInterfaces()
+	if len(ms) != 2 {
+		t.Fatalf("GetManagementControllerHostInterfaces: got %v", ms)
+	}
+	for i, want := range []struct {
+		typ    NetworkHostInterfaceDeviceType
+		serial string
+		mac    string
+	}{
+		{NetworkHostInterfaceDeviceTypeUSBV2, "SER-2", "0a:0b:0c:0d:0e:0f"},
+		{NetworkHostInterfaceDeviceTypeUSB, "SN1", ""},
+	} {
+		d := ms[i].Device
+		if d == nil || d.Type != want.typ || d.VendorID != 0x1d6b || d.DeviceID != 0x0103 ||
+			d.SerialNumber != want.serial || d.MACAddress.String() != want.mac || d.BusAddress() != "" {
+			t.Errorf("USB device %d: got %+v", i, d)
+		}
+		if len(ms[i].Protocols) != 0 {
+			t.Errorf("USB device %d: unexpected protocols %v", i, ms[i].Protocols)
+		}
+	}
+}
+
+func TestManagementControllerHostInterfaceTruncated(t *testing.T) {
+	rf := redfishRecord(byte(RedfishIPAddressFormatIPv4), net.IPv4(10, 0, 0, 2), net.IPv4(10, 0, 0, 1), 443, 0, "")
+	b := networkHostInterface([]byte{byte(NetworkHostInterfaceDeviceTypePCI), 0x86, 0x80, 0x33, 0x15, 0, 0, 0, 0},
+		protocolRecord(HostInterfaceProtocolTypeRedfishOverIP, rf))
+	// Claim a second record that the structure does not hold
+	b[1+1+9]++
+	loadTable(structure(42, 0x50, b[:len(b)-10]))
+	m := GetManagementControllerHostInterface()
+	if m == nil || m.Device == nil || m.Device.DeviceID != 0x1533 || len(m.Protocols) != 0 {
+		t.Errorf("truncated records: got %v", m)
+	}
+
+	loadTable(structure(42, 0x51, []byte{byte(ManagementControllerHostInterfaceTypeOEM), 4, 0, 0, 0x01, 0x57, 0}))
+	m = GetManagementControllerHostInterface()
+	if m == nil || m.MCHostInterfaceData() != "Vendor ID:0x00000157" {
+		t.Errorf("OEM interface: got %v", m)
+	}
+}
+
+func TestRedfishOverIPURL(t *testing.T) {
+	for _, tc := range []struct {
+		r    RedfishOverIP
+		want string
+	}{
+		{RedfishOverIP{ServiceIPAddress: net.IPv4(169, 254, 0, 1), ServicePort: 443}, "https://169.254.0.1/redfish/v1/"},
+		{RedfishOverIP{ServiceIPAddress: net.IPv4(169, 254, 0, 1), ServicePort: 8443}, "https://169.254.0.1:8443/redfish/v1/"},
+		{RedfishOverIP{ServiceIPAddress: net.ParseIP("fe80::1")}, "https://[fe80::1]/redfish/v1/"},
+		{RedfishOverIP{ServiceIPAddress: net.ParseIP("fe80::1"), ServicePort: 8443}, "https://[fe80::1]:8443/redfish/v1/"},
+		{RedfishOverIP{ServiceHostname: "bmc", ServiceIPAddress: net.ParseIP("fe80::1")}, "https://bmc/redfish/v1/"},
+		{RedfishOverIP{}, ""},
+	} {
+		if got := tc.r.URL(); got != tc.want {
+			t.Errorf("URL(%+v) = %q, want %q", tc.r, got, tc.want)
+		}
+	}
+}
+
+func TestRedfishOverIPv6(t *testing.T) {
+	rf := redfishRecord(byte(RedfishIPAddressFormatIPv6), net.ParseIP("fe80::2"), net.ParseIP("fe80::1"), 443, 0, "")
+	r := newRedfishOverIP(rf)
+	if r == nil || r.HostIPAddress.String() != "fe80::2" || r.ServiceIPAddress.String() != "fe80::1" ||
+		r.ServiceHostname != "" || r.URL() != "https://[fe80::1]/redfish/v1/" {
+		t.Errorf("IPv6 record: got %+v", r)
+	}
+	if newRedfishOverIP(rf[:0x5A]) != nil {
+		t.Error("short record: expected nil")
+	}
+}
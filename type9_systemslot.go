@@ -32,7 +32,35 @@ const (
 	SystemSlotTypeAGP4X
 	SystemSlotTypePCI_X
 	SystemSlotTypeAGP8X
-	SystemSlotTypePC_98C20
+	SystemSlotTypeM2Socket1_DP
+	SystemSlotTypeM2Socket1_SD
+	SystemSlotTypeM2Socket2
+	SystemSlotTypeM2Socket3
+	SystemSlotTypeMXMTypeI
+	SystemSlotTypeMXMTypeII
+	SystemSlotTypeMXMTypeIIIStandardConnector
+	SystemSlotTypeMXMTypeIIIHEConnector
+	SystemSlotTypeMXMTypeIV
+	SystemSlotTypeMXM3_0TypeA
+	SystemSlotTypeMXM3_0TypeB
+	SystemSlotTypePCIExpressGen2SFF_8639
+	SystemSlotTypePCIExpressGen3SFF_8639
+	SystemSlotTypePCIExpressMini52pinWithBottomSideKeepOuts
+	SystemSlotTypePCIExpressMini52pinWithoutBottomSideKeepOuts
+	SystemSlotTypePCIExpressMini76pin
+	SystemSlotTypePCIExpressGen4SFF_8639
+	SystemSlotTypePCIExpressGen5SFF_8639
+	SystemSlotTypeOCPNIC3_0SmallFormFactor
+	SystemSlotTypeOCPNIC3_0LargeFormFactor
+	SystemSlotTypeOCPNICPriorTo3_0
+)
+
+const (
+	SystemSlotTypeCXLFlexbus1_0 SystemSlotType = 0x30
+)
+
+const (
+	SystemSlotTypePC_98C20 SystemSlotType = 0xA0 + iota
 	SystemSlotTypePC_98C24
 	SystemSlotTypePC_98E
 	SystemSlotTypePC_98LocalBus
@@ -55,11 +83,27 @@ const (
 	SystemSlotTypePCIExpressGen3x4
 	SystemSlotTypePCIExpressGen3x8
 	SystemSlotTypePCIExpressGen3x16
+	_
+	SystemSlotTypePCIExpressGen4
+	SystemSlotTypePCIExpressGen4x1
+	SystemSlotTypePCIExpressGen4x2
+	SystemSlotTypePCIExpressGen4x4
+	SystemSlotTypePCIExpressGen4x8
+	SystemSlotTypePCIExpressGen4x16
+	SystemSlotTypePCIExpressGen5
+	SystemSlotTypePCIExpressGen5x1
+	SystemSlotTypePCIExpressGen5x2
+	SystemSlotTypePCIExpressGen5x4
+	SystemSlotTypePCIExpressGen5x8
+	SystemSlotTypePCIExpressGen5x16
+	SystemSlotTypePCIExpressGen6AndBeyond
+	SystemSlotTypeEDSFFE1
+	SystemSlotTypeEDSFFE3
 )
 
 func (s SystemSlotType) String() string {
 	types := [...]string{
-		"Other",
+		"Other", /* 0x01 */
 		"Unknown",
 		"ISA",
 		"MCA",
@@ -78,7 +122,30 @@ func (s SystemSlotType) String() string {
 		"AGP 4X",
 		"PCI-X",
 		"AGP 8X",
-		"PC-98/C20",
+		"M.2 Socket 1-DP (Mechanical Key A)",
+		"M.2 Socket 1-SD (Mechanical Key E)",
+		"M.2 Socket 2 (Mechanical Key B)",
+		"M.2 Socket 3 (Mechanical Key M)",
+		"MXM Type I",
+		"MXM Type II",
+		"MXM Type III (standard connector)",
+		"MXM Type III (HE connector)",
+		"MXM Type IV",
+		"MXM 3.0 Type A",
+		"MXM 3.0 Type B",
+		"PCI Express Gen 2 SFF-8639 (U.2)",
+		"PCI Express Gen 3 SFF-8639 (U.2)",
+		"PCI Express Mini 52-pin with bottom-side keep-outs",
+		"PCI Express Mini 52-pin without bottom-side keep-outs",
+		"PCI Express Mini 76-pin",
+		"PCI Express Gen 4 SFF-8639 (U.2)",
+		"PCI Express Gen 5 SFF-8639 (U.2)",
+		"OCP NIC 3.0 Small Form Factor (SFF)",
+		"OCP NIC 3.0 Large Form Factor (LFF)",
+		"OCP NIC Prior to 3.0", /* 0x28 */
+	}
+	types2 := [...]string{
+		"PC-98/C20", /* 0xA0 */
 		"PC-98/C24",
 		"PC-98/E",
 		"PC-98/Local Bus",
@@ -101,8 +168,46 @@ func (s SystemSlotType) String() string {
 		"PCI Express Gen 3 x4",
 		"PCI Express Gen 3 x8",
 		"PCI Express Gen 3 x16",
+		OUT_OF_SPEC, /* 0xB7 */
+		"PCI Express Gen 4",
+		"PCI Express Gen 4 x1",
+		"PCI Express Gen 4 x2",
+		"PCI Express Gen 4 x4",
+		"PCI Express Gen 4 x8",
+		"PCI Express Gen 4 x16",
+		"PCI Express Gen 5",
+		"PCI Express Gen 5 x1",
+		"PCI Express Gen 5 x2",
+		"PCI Express Gen 5 x4",
+		"PCI Express Gen 5 x8",
+		"PCI Express Gen 5 x16",
+		"PCI Express Gen 6 and Beyond",
+		"Enterprise and Datacenter 1U E1 Form Factor Slot",
+		"Enterprise and Datacenter 3\" E3 Form Factor Slot", /* 0xC6 */
 	}
-	return types[s-1]
+	if s >= SystemSlotTypeOther && s <= SystemSlotTypeOCPNICPriorTo3_0 {
+		return types[s-1]
+	}
+	if s == SystemSlotTypeCXLFlexbus1_0 {
+		return "CXL Flexbus 1.0"
+	}
+	if s >= SystemSlotTypePC_98C20 && s <= SystemSlotTypeEDSFFE3 {
+		return types2[s-0xA0]
+	}
+	return OUT_OF_SPEC
+}
+
+// IsPCIExpress reports whether the slot is electrically PCI Express
+func (s SystemSlotType) IsPCIExpress() bool {
+	switch {
+	case s >= SystemSlotTypeM2Socket1_DP && s <= SystemSlotTypeOCPNICPriorTo3_0:
+		return true
+	case s == SystemSlotTypeCXLFlexbus1_0:
+		return true
+	case s >= SystemSlotTypePCIExpress && s <= SystemSlotTypeEDSFFE3:
+		return s != 0xB7
+	}
+	return false
 }
 
 type SystemSlotDataBusWidth byte
@@ -141,7 +246,32 @@ func (s SystemSlotDataBusWidth) String() string {
 		"16x or x16",
 		"32x or x32",
 	}
-	return widths[s-1]
+	if s >= SystemSlotDataBusWidthOther && s <= SystemSlotDataBusWidth32xorx32 {
+		return widths[s-1]
+	}
+	return OUT_OF_SPEC
+}
+
+// Lanes returns the number of PCI Express lanes of the width, or 0 if the
+// width is not a lane count
+func (s SystemSlotDataBusWidth) Lanes() int {
+	switch s {
+	case SystemSlotDataBusWidth1xorx1:
+		return 1
+	case SystemSlotDataBusWidth2xorx2:
+		return 2
+	case SystemSlotDataBusWidth4xorx4:
+		return 4
+	case SystemSlotDataBusWidth8xorx8:
+		return 8
+	case SystemSlotDataBusWidth12xorx12:
+		return 12
+	case SystemSlotDataBusWidth16xorx16:
+		return 16
+	case SystemSlotDataBusWidth32xorx32:
+		return 32
+	}
+	return 0
 }
 
 type SystemSlotUsage byte
@@ -151,6 +281,7 @@ const (
 	SystemSlotUsageUnknown
 	SystemSlotUsageAvailable
 	SystemSlotUsageInuse
+	SystemSlotUsageUnavailable
 )
 
 func (s SystemSlotUsage) String() string {
@@ -159,8 +290,12 @@ func (s SystemSlotUsage) String() string {
 		"Unknown",
 		"Available",
 		"In use",
+		"Unavailable",
 	}
-	return usages[s-1]
+	if s >= SystemSlotUsageOther && s <= SystemSlotUsageUnavailable {
+		return usages[s-1]
+	}
+	return OUT_OF_SPEC
 }
 
 type SystemSlotLength byte
@@ -170,6 +305,8 @@ const (
 	SystemSlotLengthUnknown
 	SystemSlotLengthShortLength
 	SystemSlotLengthLongLength
+	SystemSlotLength2_5DriveFormFactor
+	SystemSlotLength3_5DriveFormFactor
 )
 
 func (s SystemSlotLength) String() string {
@@ -178,8 +315,13 @@ func (s SystemSlotLength) String() string {
 		"Unknown",
 		"Short Length",
 		"Long Length",
+		"2.5\" drive form factor",
+		"3.5\" drive form factor",
+	}
+	if s >= SystemSlotLengthOther && s <= SystemSlotLength3_5DriveFormFactor {
+		return lengths[s-1]
 	}
-	return lengths[s-1]
+	return OUT_OF_SPEC
 }
 
 type SystemSlotID uint16
@@ -208,7 +350,13 @@ func (s SystemSlotCharacteristics1) String() string {
 		"PC Card slot supports Zoom Video.",
 		"PC Card slot supports Modem Ring Resume.",
 	}
-	return chars[s>>1]
+	var str string
+	for i := uint32(0); i < 8; i++ {
+		if s&(1<<i) != 0 {
+			str += "\n\t\t" + chars[i]
+		}
+	}
+	return str
 }
 
 type SystemSlotCharacteristics2 byte
@@ -217,23 +365,90 @@ const (
 	SystemSlotCharacteristics2PCIslotsupportsPowerManagementEventsignal SystemSlotCharacteristics2 = 1 << iota
 	SystemSlotCharacteristics2Slotsupportshot_plugdevices
 	SystemSlotCharacteristics2PCIslotsupportsSMBussignal
-	SystemSlotCharacteristics2Reserved
+	SystemSlotCharacteristics2PCIExpressslotsupportsbifurcation
+	SystemSlotCharacteristics2Slotsupportsasync_surpriseremoval
+	SystemSlotCharacteristics2FlexbusslotCXL1_0capable
+	SystemSlotCharacteristics2FlexbusslotCXL2_0capable
+	SystemSlotCharacteristics2FlexbusslotCXL3_0capable
 )
 
+// Deprecated: bit 3 is no longer reserved, use
+// SystemSlotCharacteristics2PCIExpressslotsupportsbifurcation.
+const SystemSlotCharacteristics2Reserved = SystemSlotCharacteristics2PCIExpressslotsupportsbifurcation
+
 func (s SystemSlotCharacteristics2) String() string {
 	chars := [...]string{
 		"PCI slot supports Power Management Event (PME#) signal.",
 		"Slot supports hot-plug devices.",
 		"PCI slot supports SMBus signal.",
-		"Reserved",
+		"PCIe slot supports bifurcation.",
+		"Slot supports async/surprise removal.",
+		"Flexbus slot, CXL 1.0 capable.",
+		"Flexbus slot, CXL 2.0 capable.",
+		"Flexbus slot, CXL 3.0 capable.",
 	}
-	return chars[s>>1]
+	var str string
+	for i := uint32(0); i < 8; i++ {
+		if s&(1<<i) != 0 {
+			str += "\n\t\t" + chars[i]
+		}
+	}
+	return str
 }
 
 type SystemSlotSegmengGroupNumber uint16
 
 type SystemSlotNumber byte
 
+type SystemSlotHeight byte
+
+const (
+	SystemSlotHeightNotApplicable SystemSlotHeight = iota
+	SystemSlotHeightOther
+	SystemSlotHeightUnknown
+	SystemSlotHeightFullHeight
+	SystemSlotHeightLowProfile
+)
+
+func (s SystemSlotHeight) String() string {
+	heights := [...]string{
+		"Not applicable",
+		"Other",
+		"Unknown",
+		"Full height",
+		"Low-profile",
+	}
+	if s <= SystemSlotHeightLowProfile {
+		return heights[s]
+	}
+	return OUT_OF_SPEC
+}
+
+// SystemSlotPeerDevice is a Segment/Bus/Device/Function peer of a slot,
+// such as the other half of a bifurcated slot
+type SystemSlotPeerDevice struct {
+	SegmentGroupNumber   uint16
+	BusNumber            byte
+	DeviceFunctionNumber byte
+	DataBusWidth         byte
+}
+
+func (p SystemSlotPeerDevice) String() string {
+	return fmt.Sprintf("%s (Width %d)",
+		busAddress(p.SegmentGroupNumber, p.BusNumber, p.DeviceFunctionNumber),
+		p.DataBusWidth)
+}
+
+type SystemSlotPeerDevices []SystemSlotPeerDevice
+
+func (s SystemSlotPeerDevices) String() string {
+	var str string
+	for _, p := range s {
+		str += "\n\t\t" + p.String()
+	}
+	return str
+}
+
 type SystemSlot struct {
 	infoCommon
 	Designation          string
@@ -247,21 +462,65 @@ type SystemSlot struct {
 	SegmentGroupNumber   SystemSlotSegmengGroupNumber
 	BusNumber            SystemSlotNumber
 	DeviceFunctionNumber SystemSlotNumber
+	BaseDataBusWidth     byte
+	PeerGroupingCount    byte
+	PeerDevices          SystemSlotPeerDevices
+	SlotInformation      byte
+	SlotPhysicalWidth    SystemSlotDataBusWidth
+	SlotPitch            uint16
+	SlotHeight           SystemSlotHeight
+}
+
+// BusAddress returns the PCI address of the slot as segment:bus:device.function,
+// or an empty string if the slot has none
+func (s SystemSlot) BusAddress() string {
+	return busAddress(uint16(s.SegmentGroupNumber), byte(s.BusNumber), byte(s.DeviceFunctionNumber))
+}
+
+// PCIExpressGeneration returns the PCI Express generation of the slot, taken
+// from the slot information when present and from the slot type otherwise.
+// It returns 0 if the generation is not known.
+func (s SystemSlot) PCIExpressGeneration() int {
+	if !s.Type.IsPCIExpress() {
+		return 0
+	}
+	if s.SlotInformation != 0 {
+		return int(s.SlotInformation)
+	}
+	switch {
+	case s.Type >= SystemSlotTypePCIExpress && s.Type <= SystemSlotTypePCIExpressx16:
+		return 1
+	case s.Type >= SystemSlotTypePCIExpressGen2 && s.Type <= SystemSlotTypePCIExpressGen2x16:
+		return 2
+	case s.Type >= SystemSlotTypePCIExpressGen3 && s.Type <= SystemSlotTypePCIExpressGen3x16:
+		return 3
+	case s.Type >= SystemSlotTypePCIExpressGen4 && s.Type <= SystemSlotTypePCIExpressGen4x16:
+		return 4
+	case s.Type >= SystemSlotTypePCIExpressGen5 && s.Type <= SystemSlotTypePCIExpressGen5x16:
+		return 5
+	case s.Type == SystemSlotTypePCIExpressGen6AndBeyond:
+		return 6
+	case s.Type == SystemSlotTypePCIExpressGen2SFF_8639:
+		return 2
+	case s.Type == SystemSlotTypePCIExpressGen3SFF_8639:
+		return 3
+	case s.Type == SystemSlotTypePCIExpressGen4SFF_8639:
+		return 4
+	case s.Type == SystemSlotTypePCIExpressGen5SFF_8639:
+		return 5
+	}
+	return 0
 }
 
 func (s SystemSlot) String() string {
-	return fmt.Sprintf("System Slot %s\n"+
-		"\tSlot Designation: %s\n"+
-		"\tSlot Type: %s\n"+
-		"\tSlot Data Bus Width: %s\n"+
+	str := fmt.Sprintf("System Slot Information\n"+
+		"\tDesignation: %s\n"+
+		"\tType: %s\n"+
+		"\tData Bus Width: %s\n"+
 		"\tCurrent Usage: %s\n"+
-		"\tSlot Length: %s\n"+
-		"\tSlot ID: %s\n"+
-		"\tSlot Characteristics1: %s\n"+
-		"\tSlot Characteristics2: %s\n"+
-		"\tSegment Group Number: %s\n"+
-		"\tBus Number: %s\n"+
-		"\tDevice/Function Number: %s",
+		"\tLength: %s\n"+
+		"\tID: %d\n"+
+		"\tCharacteristics:%s%s",
 		s.Designation,
 		s.Type,
 		s.DataBusWidth,
@@ -269,27 +528,84 @@ func (s SystemSlot) String() string {
 		s.Length,
 		s.ID,
 		s.Characteristics1,
-		s.Characteristics2,
-		s.SegmentGroupNumber,
-		s.BusNumber,
-		s.DeviceFunctionNumber)
+		s.Characteristics2)
+	if ba := s.BusAddress(); ba != "" {
+		str += "\n\tBus Address: " + ba
+	}
+	length := int(s.infoCommon.Length)
+	if length > 0x12 {
+		str += fmt.Sprintf("\n\tData Bus Width (Base): %d\n"+
+			"\tPeer Devices: %d%s",
+			s.BaseDataBusWidth,
+			s.PeerGroupingCount,
+			s.PeerDevices)
+	}
+	if length >= 0x17+5*int(s.PeerGroupingCount) {
+		if s.Type.IsPCIExpress() && s.SlotInformation != 0 {
+			str += fmt.Sprintf("\n\tPCI Express Generation: %d", s.SlotInformation)
+		} else {
+			str += fmt.Sprintf("\n\tSlot Information: %d", s.SlotInformation)
+		}
+		str += fmt.Sprintf("\n\tSlot Physical Width: %s\n"+
+			"\tPitch: %d.%02d mm",
+			s.SlotPhysicalWidth,
+			s.SlotPitch/100,
+			s.SlotPitch%100)
+	}
+	if length >= 0x18+5*int(s.PeerGroupingCount) {
+		str += fmt.Sprintf("\n\tHeight: %s", s.SlotHeight)
+	}
+	return str
 }
 
 func newSystemSlot(h dmiHeader) dmiTyper {
 	data := h.data
-	return &SystemSlot{
+	ss := &SystemSlot{
 		Designation:          h.FieldString(int(data[0x04])),
 		Type:                 SystemSlotType(data[0x05]),
 		DataBusWidth:         SystemSlotDataBusWidth(data[0x06]),
 		CurrentUsage:         SystemSlotUsage(data[0x07]),
 		Length:               SystemSlotLength(data[0x08]),
-		ID:                   SystemSlotID(u16(data[0x09:0x0A])),
+		ID:                   SystemSlotID(u16(data[0x09:0x0B])),
 		Characteristics1:     SystemSlotCharacteristics1(data[0x0B]),
-		Characteristics2:     SystemSlotCharacteristics2(data[0x0C]),
-		SegmentGroupNumber:   SystemSlotSegmengGroupNumber(u16(data[0x0D:0x0F])),
-		BusNumber:            SystemSlotNumber(data[0x0F]),
-		DeviceFunctionNumber: SystemSlotNumber(data[0x10]),
+		SegmentGroupNumber:   0xFFFF,
+		BusNumber:            0xFF,
+		DeviceFunctionNumber: 0xFF,
+	}
+	if h.Length > 0x0C {
+		ss.Characteristics2 = SystemSlotCharacteristics2(data[0x0C])
+	}
+	if h.Length > 0x10 {
+		ss.SegmentGroupNumber = SystemSlotSegmengGroupNumber(u16(data[0x0D:0x0F]))
+		ss.BusNumber = SystemSlotNumber(data[0x0F])
+		ss.DeviceFunctionNumber = SystemSlotNumber(data[0x10])
 	}
+	if h.Length > 0x12 {
+		ss.BaseDataBusWidth = data[0x11]
+		ss.PeerGroupingCount = data[0x12]
+	}
+	for i := 0; i < int(ss.PeerGroupingCount); i++ {
+		offset := 0x13 + 5*i
+		if offset+5 > int(h.Length) {
+			break
+		}
+		ss.PeerDevices = append(ss.PeerDevices, SystemSlotPeerDevice{
+			SegmentGroupNumber:   u16(data[offset : offset+2]),
+			BusNumber:            data[offset+2],
+			DeviceFunctionNumber: data[offset+3],
+			DataBusWidth:         data[offset+4],
+		})
+	}
+	offset := 0x13 + 5*int(ss.PeerGroupingCount)
+	if int(h.Length) >= offset+4 {
+		ss.SlotInformation = data[offset]
+		ss.SlotPhysicalWidth = SystemSlotDataBusWidth(data[offset+1])
+		ss.SlotPitch = u16(data[offset+2 : offset+4])
+	}
+	if int(h.Length) >= offset+5 {
+		ss.SlotHeight = SystemSlotHeight(data[offset+4])
+	}
+	return ss
 }
 
 func GetSystemSlot() *SystemSlot {
@@ -299,6 +615,14 @@ func GetSystemSlot() *SystemSlot {
 	return nil
 }
 
+func GetSystemSlots() []*SystemSlot {
+	var ss []*SystemSlot
+	for _, d := range GetStructures(SMBIOSStructureTypeSystemSlots) {
+		ss = append(ss, d.(*SystemSlot))
+	}
+	return ss
+}
+
 func init() {
 	addTypeFunc(SMBIOSStructureTypeSystemSlots, newSystemSlot)
 }
@@ -0,0 +1,66 @@
+package godmi
+
+import "testing"
+
+// systemSlot builds a PCI Express Gen4 x16 slot at 0000:03:00.1, whose slot
+// information reports Gen5, with the formatted area cut to length
+func systemSlot(handle uint16, length int, peers ...SystemSlotPeerDevice) []byte {
+	b := []byte{1, byte(SystemSlotTypePCIExpressGen4x16), 0x0D, 4, 4, 1, 0, 0x04, 0x01,
+		0, 0, 0x03, 0x01, 16, byte(len(peers))}
+	for _, p := range peers {
+		b = append(b, byte(p.SegmentGroupNumber), byte(p.SegmentGroupNumber>>8),
+			p.BusNumber, p.DeviceFunctionNumber, p.DataBusWidth)
+	}
+	b = append(b, 5, 0x0D, 0xF0, 0x07, byte(SystemSlotHeightLowProfile))
+	return structure(9, handle, b[:length-4], "SLOT1")
+}
+
+func TestNewSystemSlot(t *testing.T) {
+	peers := []SystemSlotPeerDevice{{0, 0x04, 0x00, 8}, {1, 0x05, 0x0A, 8}}
+	loadTable(
+		// SMBIOS 2.1 ends after the first characteristics
+		systemSlot(0x0900, 0x0C),
+		// SMBIOS 2.6 adds the bus address
+		systemSlot(0x0901, 0x11),
+		// The peer count claims two devices the structure does not hold
+		systemSlot(0x0902, 0x13, peers...),
+		// SMBIOS 3.4 ends before the height
+		systemSlot(0x0903, 0x13+5*2+4, peers...),
+		systemSlot(0x0904, 0x13+5*2+5, peers...))
+	ss := GetSystemSlots()
+	if len(ss) != 5 {
+		t.Fatalf("GetSystemSlots: got %d", len(ss))
+	}
+	want := []struct {
+		char2   SystemSlotCharacteristics2
+		address string
+		peers   int
+		gen     int
+		width   SystemSlotDataBusWidth
+		pitch   uint16
+		height  SystemSlotHeight
+	}{
+		{0, "", 0, 4, 0, 0, 0},
+		{0x01, "0000:03:00.1", 0, 4, 0, 0, 0},
+		{0x01, "0000:03:00.1", 0, 4, 0, 0, 0},
+		// The slot information overrides the generation of the type
+		{0x01, "0000:03:00.1", 2, 5, 0x0D, 2032, 0},
+		{0x01, "0000:03:00.1", 2, 5, 0x0D, 2032, SystemSlotHeightLowProfile},
+	}
+	for i, w := range want {
+		s := ss[i]
+		if s.Designation != "SLOT1" || s.Characteristics2 != w.char2 || s.BusAddress() != w.address ||
+			len(s.PeerDevices) != w.peers || s.PCIExpressGeneration() != w.gen ||
+			s.SlotPhysicalWidth != w.width || s.SlotPitch != w.pitch || s.SlotHeight != w.height {
+			t.Errorf("slot %d: got %s", i, s)
+		}
+	}
+	for i, p := range peers {
+		if ss[4].PeerDevices[i] != p {
+			t.Errorf("peer %d: got %s, want %s", i, ss[4].PeerDevices[i], p)
+		}
+	}
+	if s := ss[4].PeerDevices[1].String(); s != "0001:05:01.2 (Width 8)" {
+		t.Errorf("peer String: got %q", s)
+	}
+}
@@ -69,3 +69,27 @@ func uuid(data []byte, ver string) string {
 		data[0], data[1], data[2], data[3], data[4], data[5], data[6], data[7],
 		data[8], data[9], data[10], data[11], data[12], data[13], data[14], data[15])
 }
+
+// busAddress formats a PCI segment group, bus and device/function number as
+// segment:bus:device.function, or returns "" when the structure marks any of
+// them as not provided
+func busAddress(segment uint16, bus byte, devfn byte) string {
+	if segment == 0xFFFF || bus == 0xFF || devfn == 0xFF {
+		return ""
+	}
+	return fmt.Sprintf("%04x:%02x:%02x.%x", segment, bus, devfn>>3, devfn&0x7)
+}
+
+func enabled(b bool) string {
+	if b {
+		return "Enabled"
+	}
+	return "Disabled"
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}